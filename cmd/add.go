@@ -5,13 +5,17 @@ import (
 	"os"
 	"strings"
 
+	"github.com/prime-run/togo/pkg/togo"
 	"github.com/spf13/cobra"
 )
 
 var addCmd = &cobra.Command{
 	Use:   "add",
 	Short: "Add a new todo",
-	Long:  `Add a new todo to your list. The todo will be marked as pending by default.`,
+	Long: `Add a new todo to your list. The todo will be marked as pending by default.
+Use --list to put it in a named list such as "backlog" or "today",
+--estimate to size it in points ("3") or time ("2h"), and --due, --priority
+and --blocked-by to plan it (see 'togo set').`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) == 0 {
 			fmt.Println("Error: Todo title is required")
@@ -19,16 +23,40 @@ var addCmd = &cobra.Command{
 			os.Exit(1)
 		}
 		title := strings.Join(args, " ")
+		list, _ := cmd.Flags().GetString("list")
+		estimateFlag, _ := cmd.Flags().GetString("estimate")
+		estimate, err := togo.ParseEstimate(estimateFlag)
+		handleErrorAndExit(err, "Error:")
+		client := openClientOrExit()
+		var tasks []togo.Task
+		if cmd.Flags().Changed("blocked-by") {
+			tasks = loadTasksOrExit(client)
+		}
+		plan := planningFlagsOrExit(cmd, tasks)
+		draft := togo.Task{Title: title, List: list, Estimate: estimate, Due: plan.due, Priority: plan.priority}
+		for _, b := range plan.blockers {
+			draft.BlockedBy = append(draft.BlockedBy, b.UID)
+		}
 
-		todoList := loadTodoListOrExit()
-		todo := todoList.Add(title)
-		saveTodoListOrExit(todoList)
+		var todo togo.Task
+		updateOrExit(client, func(l *togo.List) error {
+			todo, err = l.AddTask(draft)
+			return err
+		})
 
 		fmt.Printf("Todo added successfully with ID: %d\n", todo.ID)
 		fmt.Printf("Title: %s\n", todo.Title)
+		if todo.List != "" {
+			fmt.Printf("List: %s\n", todo.List)
+		}
+		printPlanning(todo, tasks)
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(addCmd)
+	addCmd.Flags().StringP("list", "l", "", "named list to add the todo to")
+	_ = addCmd.RegisterFlagCompletionFunc("list", completeListNames)
+	addCmd.Flags().StringP("estimate", "e", "", "estimate in points (3) or time (2h30m)")
+	addPlanningFlags(addCmd)
 }
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prime-run/togo/config"
+	"github.com/prime-run/togo/model"
+	"github.com/prime-run/togo/report"
+	"github.com/spf13/cobra"
+)
+
+var agendaCmd = &cobra.Command{
+	Use:   "agenda",
+	Short: "Show open todos grouped by when they are due",
+	Long: `Show the open todos in the sections Overdue, Today, This week, Later and
+No date, each ordered by importance as for 'togo next'. Set due dates and
+priorities with 'togo add --due/--priority' or 'togo set'.`,
+	Annotations: map[string]string{allSourcesAnnotation: "true"},
+	Args:        cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfigOrExit()
+		now := time.Now()
+		sections := report.BuildAgenda(loadTasksOrExit(openStoreOrExit(cmd)), now, cfg.Scoring)
+		if len(sections) == 0 {
+			fmt.Println("Nothing to do. Add a todo with 'togo add'.")
+			return
+		}
+		width := 0
+		for _, s := range sections {
+			for _, r := range s.Tasks {
+				width = max(width, min(len([]rune(r.Todo.Title)), 40))
+			}
+		}
+		for i, s := range sections {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("%s (%d)\n", s.Name, len(s.Tasks))
+			for _, r := range s.Tasks {
+				var notes []string
+				if !r.Todo.Due.IsZero() {
+					notes = append(notes, "due "+formatDue(r.Todo.Due, now))
+				}
+				if len(r.Blockers) > 0 {
+					notes = append(notes, "blocked by "+blockerTitles(r.Blockers))
+				}
+				fmt.Printf("  %3d  %-3s  %-*s  %s\n", r.Todo.ID, priorityMarker(r.Todo.Priority),
+					width, truncateTitle(r.Todo.Title, 40), strings.Join(notes, ", "))
+			}
+		}
+	},
+}
+
+var nextCmd = &cobra.Command{
+	Use:   "next",
+	Short: "Show the most important todo to work on",
+	Long: `Show the open, unblocked todo with the highest score. The score adds up
+the priority, how close or overdue the due date is, the age of the todo and a
+penalty for being blocked, each multiplied by a weight that can be changed in
+the "scoring" section of config.json. --explain shows the calculation.`,
+	Annotations: map[string]string{allSourcesAnnotation: "true"},
+	Args:        cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		explain, _ := cmd.Flags().GetBool("explain")
+		cfg := loadConfigOrExit()
+		now := time.Now()
+		next, ok := report.Next(loadTasksOrExit(openStoreOrExit(cmd)), now, cfg.Scoring)
+		if !ok {
+			fmt.Println("Nothing to do: every open todo is blocked or there are none.")
+			return
+		}
+		todo := next.Todo
+		fmt.Printf("%s  (ID %d)\n", todo.Title, todo.ID)
+		details := []string{model.HumanizeDuration(now.Sub(todo.CreatedAt)) + " old"}
+		if todo.Priority != model.PriorityNone {
+			details = append([]string{"priority " + todo.Priority.String()}, details...)
+		}
+		if !todo.Due.IsZero() {
+			details = append(details, "due "+formatDue(todo.Due, now))
+		}
+		fmt.Println("  " + strings.Join(details, " · "))
+		if explain {
+			p := next.Parts
+			fmt.Printf("  score %.2f = priority %.2f + due %.2f + age %.2f + blocked %.2f\n",
+				next.Score, p.Priority, p.Due, p.Age, p.Blocked)
+		}
+	},
+}
+
+func loadConfigOrExit() config.Config {
+	cfg, err := config.Load()
+	handleErrorAndExit(err, "Error loading config:")
+	return cfg
+}
+
+// formatDue shows a due date as "today", "tomorrow", "yesterday" or a date,
+// with the time if it has one, and how long ago it was when it has passed.
+func formatDue(due, now time.Time) string {
+	today := report.StartOfDay(now)
+	var s string
+	switch day := report.StartOfDay(due); {
+	case day.Equal(today):
+		s = "today"
+	case day.Equal(today.AddDate(0, 0, 1)):
+		s = "tomorrow"
+	case day.Equal(today.AddDate(0, 0, -1)):
+		s = "yesterday"
+	case day.Year() == today.Year():
+		s = due.Format("Mon Jan 2")
+	default:
+		s = due.Format("Mon Jan 2 2006")
+	}
+	if model.HasTime(due) {
+		s += " " + due.Format("15:04")
+	}
+	if report.Deadline(due).Before(now) && report.StartOfDay(due).Before(today.AddDate(0, 0, -1)) {
+		s += " (" + model.HumanizeTime(due, now) + ")"
+	}
+	return s
+}
+
+func priorityMarker(p model.Priority) string {
+	return strings.Repeat("!", int(p))
+}
+
+func blockerTitles(blockers []model.Todo) string {
+	titles := make([]string, len(blockers))
+	for i, b := range blockers {
+		titles[i] = fmt.Sprintf("%q", truncateTitle(b.Title, 30))
+	}
+	return strings.Join(titles, ", ")
+}
+
+func truncateTitle(title string, n int) string {
+	if r := []rune(title); len(r) > n {
+		return string(r[:n-1]) + "…"
+	}
+	return title
+}
+
+func init() {
+	rootCmd.AddCommand(agendaCmd, nextCmd)
+	nextCmd.Flags().Bool("explain", false, "show how the score was calculated")
+}
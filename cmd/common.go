@@ -1,30 +1,175 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 
+	"github.com/manifoldco/promptui"
+	"github.com/prime-run/togo/config"
+	"github.com/prime-run/togo/hooks"
 	"github.com/prime-run/togo/model"
+	"github.com/prime-run/togo/pkg/togo"
+	"github.com/prime-run/togo/ui"
+	"github.com/spf13/cobra"
 )
 
-func loadTodoListOrExit() *model.TodoList {
-	todoList, err := model.LoadTodoListWithSource(TodoFileName, sourceFlag)
+func openClientOrExit() *togo.Client {
+	if sourceFlag == togo.All {
+		handleErrorAndExit(errSourceAllViewOnly, "Error:")
+	}
+	client, err := togo.Open(sourceFlag, clientOptions()...)
+	handleErrorAndExit(err, "Error:")
+	return client
+}
+
+// openStoreOrExit is openClientOrExit for commands that can show several
+// sources at once: with --source all or --recursive it combines every project
+// below the working directory and the global list.
+func openStoreOrExit(cmd *cobra.Command) togo.Store {
+	if recursive, _ := cmd.Flags().GetBool("recursive"); recursive {
+		sourceFlag = togo.All
+	}
+	if sourceFlag != togo.All {
+		return openClientOrExit()
+	}
+	store, err := togo.OpenAll(clientOptions()...)
+	handleErrorAndExit(err, "Error:")
+	return store
+}
+
+// clientOptions returns the options commands open clients with, including
+// the retention policy when config.json asks for it to be applied on load.
+func clientOptions() []togo.Option {
+	opts := []togo.Option{togo.WithFileName(TodoFileName), togo.WithHookOutput(os.Stderr)}
+	if cfg, err := config.Load(); err == nil && cfg.Retention.OnLoad {
+		opts = append(opts, togo.WithRetention(retentionPolicy(cfg.Retention)))
+	}
+	return opts
+}
+
+func loadTasksOrExit(store togo.Store) []togo.Task {
+	tasks, err := store.Tasks(context.Background())
 	if err != nil {
 		fmt.Println("Error loading todos:", err)
 		os.Exit(1)
 	}
-	return todoList
+	return tasks
 }
 
-func saveTodoListOrExit(todoList *model.TodoList) {
-	if err := todoList.SaveWithSource(TodoFileName, sourceFlag); err != nil {
+// updateOrExit applies fn through the client. A hook rejecting the change
+// exits with its message; hooks failing after the save only warn.
+func updateOrExit(client *togo.Client, fn func(*togo.List) error) {
+	err := client.Update(context.Background(), fn)
+	var postErr *togo.PostHookError
+	var hookErr *hooks.Error
+	switch {
+	case err == nil:
+	case errors.As(err, &postErr):
+		fmt.Println("Warning:", postErr.Err)
+	case errors.As(err, &hookErr):
+		fmt.Println("Aborted by hook:", err)
+		os.Exit(1)
+	case errors.Is(err, togo.ErrTimerRunning), errors.Is(err, togo.ErrNotFound):
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	default:
 		fmt.Println("Error saving todos:", err)
 		os.Exit(1)
 	}
 }
 
-func checkEmptyTodoList(todoList *model.TodoList, emptyMessage string) bool {
-	if len(todoList.Todos) == 0 {
+// saveTableOrExit saves the list edited in the TUI once it exits. Hooks
+// failing after the save only warn.
+func saveTableOrExit(ctx context.Context, m *ui.TodoTableModel) {
+	err := m.Save(ctx)
+	var postErr *togo.PostHookError
+	if errors.As(err, &postErr) {
+		fmt.Println("Warning:", postErr.Err)
+		return
+	}
+	handleErrorAndExit(err, "Error saving todos:")
+}
+
+func activeTasks(tasks []togo.Task) []togo.Task {
+	var active []togo.Task
+	for _, task := range tasks {
+		if !task.Archived {
+			active = append(active, task)
+		}
+	}
+	return active
+}
+
+func archivedTasks(tasks []togo.Task) []togo.Task {
+	var archived []togo.Task
+	for _, task := range tasks {
+		if task.Archived {
+			archived = append(archived, task)
+		}
+	}
+	return archived
+}
+
+// completeTaskTitles completes the first argument with the titles of the
+// tasks keep accepts.
+func completeTaskTitles(keep func(togo.Task) bool) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		tasks, err := completionTasks(cmd)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		var titles []string
+		for _, task := range tasks {
+			if keep(task) && strings.Contains(strings.ToLower(task.Title), strings.ToLower(toComplete)) {
+				titles = append(titles, task.Title)
+			}
+		}
+		return titles, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+func completeListNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	tasks, err := completionTasks(cmd)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return togo.ListNames(tasks), cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeProjectNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	projects, err := model.LoadProjects()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	names := make([]string, len(projects))
+	for i, p := range projects {
+		names[i] = p.Name
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completionTasks loads the tasks of the current source for shell completion,
+// which runs without the persistent pre-run.
+func completionTasks(cmd *cobra.Command) ([]togo.Task, error) {
+	if err := applyPersistentFlags(); err != nil {
+		return nil, err
+	}
+	client, err := togo.Open(sourceFlag, togo.WithFileName(TodoFileName))
+	if err != nil {
+		return nil, err
+	}
+	return client.Tasks(cmd.Context())
+}
+
+func checkEmptyTodoList(tasks []togo.Task, emptyMessage string) bool {
+	if len(tasks) == 0 {
 		fmt.Println(emptyMessage)
 		return true
 	}
@@ -37,3 +182,61 @@ func handleErrorAndExit(err error, message string) {
 		os.Exit(1)
 	}
 }
+
+func resolveTodoArgOrExit(todos []model.Todo, args []string, label string) model.Todo {
+	if len(todos) == 0 {
+		fmt.Println("No todos found. Add some todos with the 'add' command.")
+		os.Exit(1)
+	}
+	if len(args) == 0 {
+		return promptTodoOrExit(todos, label)
+	}
+	query := args[0]
+	for _, todo := range todos {
+		if strings.EqualFold(todo.Title, query) {
+			return todo
+		}
+	}
+	if id, err := strconv.Atoi(query); err == nil {
+		for _, todo := range todos {
+			if todo.ID == id {
+				return todo
+			}
+		}
+	}
+	var matches []model.Todo
+	for _, todo := range todos {
+		if strings.Contains(strings.ToLower(todo.Title), strings.ToLower(query)) {
+			matches = append(matches, todo)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		fmt.Printf("Error: No todos found matching \"%s\"\n", query)
+		os.Exit(1)
+	case 1:
+		return matches[0]
+	}
+	return promptTodoOrExit(matches, label)
+}
+
+func promptTodoOrExit(todos []model.Todo, label string) model.Todo {
+	templates := &promptui.SelectTemplates{
+		Label:    "{{ . }}",
+		Active:   "▶ {{ .Title | cyan }} {{ if .Completed }}(Completed){{ else }}(Pending){{ end }}",
+		Inactive: "  {{ .Title }} {{ if .Completed }}(Completed){{ else }}(Pending){{ end }}",
+		Selected: "✓ {{ .Title | green }} {{ if .Completed }}(Completed){{ else }}(Pending){{ end }}",
+	}
+	prompt := promptui.Select{
+		Label:     label,
+		Items:     todos,
+		Templates: templates,
+		Size:      10,
+	}
+	index, _, err := prompt.Run()
+	if err != nil {
+		fmt.Println("Operation cancelled")
+		os.Exit(0)
+	}
+	return todos[index]
+}
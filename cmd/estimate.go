@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/prime-run/togo/pkg/togo"
+	"github.com/spf13/cobra"
+)
+
+var estimateCmd = &cobra.Command{
+	Use:   "estimate [title] <estimate>",
+	Short: "Set the estimate of a todo",
+	Long: `Set how big a todo is, in story points ("3", "2.5pt") or as a duration ("90m",
+"2h30m"). Use "none" to clear the estimate. Without a title you are asked to
+pick the todo. Estimates feed 'togo report burndown'.`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		estimate, err := togo.ParseEstimate(args[len(args)-1])
+		handleErrorAndExit(err, "Error:")
+		client := openClientOrExit()
+		todo := resolveTodoArgOrExit(loadTasksOrExit(client), args[:len(args)-1], "Select a todo to estimate")
+		updateOrExit(client, func(l *togo.List) error {
+			return l.SetEstimate(todo.ID, estimate)
+		})
+		if estimate.IsZero() {
+			fmt.Printf("Estimate of \"%s\" cleared\n", todo.Title)
+			return
+		}
+		fmt.Printf("Todo \"%s\" estimated at %s\n", todo.Title, estimate)
+	},
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completeTaskTitles(func(t togo.Task) bool { return !t.Archived })(cmd, args, toComplete)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(estimateCmd)
+}
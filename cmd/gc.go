@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prime-run/togo/config"
+	"github.com/prime-run/togo/pkg/togo"
+	"github.com/spf13/cobra"
+)
+
+var gcCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Archive and purge old todos by retention policy",
+	Long: `Clean up by the "retention" policy in config.json, or the flags: archive
+todos completed more than --archive-after days ago, then purge archived todos
+archived more than --purge-after days ago and all but the --keep most recently
+archived ones. Purged todos are not lost: they are appended, one JSON object
+per line, to a gzip-compressed file next to the todo file
+(todos.purged.jsonl.gz; read it with 'zcat').
+
+Set "on_load": true in the policy to apply it whenever togo loads the todos.
+--dry-run shows what would happen without changing anything.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		r := loadConfigOrExit().Retention
+		for flag, value := range map[string]*int{"archive-after": &r.ArchiveAfterDays, "purge-after": &r.PurgeAfterDays, "keep": &r.KeepArchived} {
+			if cmd.Flags().Changed(flag) {
+				*value, _ = cmd.Flags().GetInt(flag)
+			}
+		}
+		if r.ArchiveAfterDays < 0 || r.PurgeAfterDays < 0 || r.KeepArchived < 0 {
+			handleErrorAndExit(fmt.Errorf("--archive-after, --purge-after and --keep must not be negative"), "Error:")
+		}
+		policy := retentionPolicy(r)
+		if policy.IsZero() {
+			fmt.Println(`No retention policy set. Add a "retention" section to config.json or use --archive-after, --purge-after or --keep.`)
+			return
+		}
+		client := openClientOrExit()
+		collected, err := client.Collect(context.Background(), policy, dryRun)
+		handleErrorAndExit(err, "Error:")
+		if collected.IsEmpty() {
+			fmt.Println("Nothing to clean up.")
+			return
+		}
+		archiveVerb, purgeVerb := "Archived", "Purged"
+		if dryRun {
+			archiveVerb, purgeVerb = "Would archive", "Would purge"
+		}
+		if n := len(collected.Archived); n > 0 {
+			fmt.Printf("%s %d completed %s:\n", archiveVerb, n, pluralTodos(n))
+			for _, task := range collected.Archived {
+				fmt.Printf("  %3d  %s\n", task.ID, task.Title)
+			}
+		}
+		if n := len(collected.Purged); n > 0 {
+			fmt.Printf("%s %d archived %s into %s:\n", purgeVerb, n, pluralTodos(n), client.PurgeFile())
+			for _, task := range collected.Purged {
+				fmt.Printf("  %3d  %s\n", task.ID, task.Title)
+			}
+		}
+	},
+}
+
+// retentionPolicy turns the retention settings of config.json into a policy.
+func retentionPolicy(r config.Retention) togo.Retention {
+	const day = 24 * time.Hour
+	return togo.Retention{
+		ArchiveAfter: time.Duration(r.ArchiveAfterDays) * day,
+		PurgeAfter:   time.Duration(r.PurgeAfterDays) * day,
+		KeepArchived: r.KeepArchived,
+	}
+}
+
+func pluralTodos(n int) string {
+	if n == 1 {
+		return "todo"
+	}
+	return "todos"
+}
+
+func init() {
+	rootCmd.AddCommand(gcCmd)
+	gcCmd.Flags().Bool("dry-run", false, "show what would be archived and purged without changing anything")
+	gcCmd.Flags().Int("archive-after", 0, "archive todos completed more than this many days ago")
+	gcCmd.Flags().Int("purge-after", 0, "purge todos archived more than this many days ago")
+	gcCmd.Flags().Int("keep", 0, "purge all but this many most recently archived todos")
+}
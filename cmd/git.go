@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/prime-run/togo/git"
+	"github.com/prime-run/togo/hooks"
+	"github.com/prime-run/togo/pkg/togo"
+	"github.com/spf13/cobra"
+)
+
+var gitCmd = &cobra.Command{
+	Use:   "git",
+	Short: "Link tasks to git branches and commits",
+	Long: `Integrate togo with the git repository of the current project.
+
+Commit messages can reference tasks as togo#<id>. Mentions link the commit to
+the task; a closing keyword (fix, fixes, fixed, close, closes, closed,
+resolve, resolves, resolved) also marks the task as completed once the
+commit is made. Install the hooks that do this with 'togo git install-hooks'.`,
+}
+
+var gitInstallHooksCmd = &cobra.Command{
+	Use:   "install-hooks",
+	Short: "Install commit-msg and post-commit hooks in the current repository",
+	Run: func(cmd *cobra.Command, args []string) {
+		force, _ := cmd.Flags().GetBool("force")
+		installed, err := git.InstallHooks(".", force)
+		for _, path := range installed {
+			fmt.Println("Installed", path)
+		}
+		handleErrorAndExit(err, "Error installing hooks:")
+	},
+}
+
+var gitBranchCmd = &cobra.Command{
+	Use:   "branch [task]",
+	Short: "Create (or switch to) a branch named after a task",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := openClientOrExit()
+		todo := resolveTodoArgOrExit(activeTasks(loadTasksOrExit(client)), args, "Select a todo to create a branch for")
+		name, err := checkoutTaskBranch(client, todo)
+		handleErrorAndExit(err, "Error creating branch:")
+		fmt.Printf("Switched to branch %s for todo \"%s\"\n", name, todo.Title)
+	},
+}
+
+var gitSetupMergeCmd = &cobra.Command{
+	Use:   "setup-merge",
+	Short: "Register togo as the merge driver for todo files in this repository",
+	Run: func(cmd *cobra.Command, args []string) {
+		root, err := git.RepoRoot(".")
+		handleErrorAndExit(err, "Error:")
+		handleErrorAndExit(git.SetConfig(root, "merge.togo.name", "togo todo list merge driver"), "Error configuring git:")
+		handleErrorAndExit(git.SetConfig(root, "merge.togo.driver", "togo merge-driver %O %A %B"), "Error configuring git:")
+		fmt.Println("Registered merge driver 'togo' in .git/config")
+
+		attr := TodoFileName + " merge=togo"
+		added, err := git.EnsureAttribute(root, attr)
+		handleErrorAndExit(err, "Error updating .gitattributes:")
+		if added {
+			fmt.Printf("Added \"%s\" to .gitattributes\n", attr)
+		}
+	},
+}
+
+var gitHookCmd = &cobra.Command{
+	Use:    "hook <commit-msg|post-commit> [args]",
+	Short:  "Entry point for the installed git hooks",
+	Hidden: true,
+	Args:   cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		switch args[0] {
+		case "commit-msg":
+			if len(args) < 2 {
+				handleErrorAndExit(fmt.Errorf("missing commit message file"), "Error:")
+			}
+			handleErrorAndExit(runCommitMsgHook(args[1]), "togo:")
+		case "post-commit":
+			handleErrorAndExit(runPostCommitHook(), "togo:")
+		default:
+			handleErrorAndExit(fmt.Errorf("unknown hook %q", args[0]), "Error:")
+		}
+	},
+}
+
+func checkoutTaskBranch(client *togo.Client, todo togo.Task) (string, error) {
+	name := git.BranchName(todo.ID, todo.Title)
+	if err := git.Checkout(".", name, !git.BranchExists(".", name)); err != nil {
+		return "", err
+	}
+	err := client.Update(context.Background(), func(l *togo.List) error {
+		return l.LinkBranch(todo.ID, name)
+	})
+	return name, err
+}
+
+func runCommitMsgHook(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(string(data), "\n")
+	body := len(lines)
+	for i, line := range lines {
+		if strings.HasPrefix(line, "#") {
+			body = i
+			break
+		}
+	}
+	message := strings.TrimSpace(strings.Join(lines[:body], "\n"))
+	if message == "" {
+		return nil
+	}
+
+	client, err := togo.Open(sourceFlag, togo.WithFileName(TodoFileName))
+	if err != nil {
+		return err
+	}
+	tasks, err := client.Tasks(context.Background())
+	if err != nil {
+		return err
+	}
+	byID := func(id int) func(togo.Task) bool {
+		return func(t togo.Task) bool { return t.ID == id }
+	}
+	refs := git.ParseRefs(message)
+	for _, ref := range refs {
+		if !slices.ContainsFunc(tasks, byID(ref.ID)) {
+			fmt.Fprintf(os.Stderr, "togo: warning: %s does not match any task\n", git.FormatRef(ref.ID))
+		}
+	}
+	if len(refs) > 0 {
+		return nil
+	}
+
+	branch, err := git.CurrentBranch(".")
+	if err != nil {
+		return nil
+	}
+	i := slices.IndexFunc(tasks, func(t togo.Task) bool { return slices.Contains(t.Branches, branch) })
+	if i < 0 {
+		if id, ok := git.BranchTaskID(branch); ok {
+			i = slices.IndexFunc(tasks, byID(id))
+		}
+	}
+	if i < 0 {
+		return nil
+	}
+	trailer := []string{message, "", "Refs: " + git.FormatRef(tasks[i].ID), ""}
+	rest := strings.Join(lines[body:], "\n")
+	return os.WriteFile(path, []byte(strings.Join(trailer, "\n")+rest), 0644)
+}
+
+func runPostCommitHook() error {
+	hash, message, err := git.HeadCommit(".")
+	if err != nil {
+		return err
+	}
+	refs := git.ParseRefs(message)
+	if len(refs) == 0 {
+		return nil
+	}
+	client, err := togo.Open(sourceFlag, togo.WithFileName(TodoFileName), togo.WithHookOutput(os.Stderr))
+	if err != nil {
+		return err
+	}
+	err = client.Update(context.Background(), func(l *togo.List) error {
+		for _, ref := range refs {
+			todo, ok := l.Task(ref.ID)
+			if !ok {
+				continue
+			}
+			if err := l.LinkCommit(todo.ID, hash); err != nil {
+				return err
+			}
+			if !ref.Closes || todo.Completed {
+				continue
+			}
+			var hookErr *hooks.Error
+			if err := l.SetCompleted(todo.ID, true); errors.As(err, &hookErr) {
+				fmt.Printf("togo: not completing \"%s\": %v\n", todo.Title, err)
+				continue
+			} else if err != nil {
+				return err
+			}
+			fmt.Printf("togo: completed \"%s\" (%s)\n", todo.Title, git.FormatRef(todo.ID))
+		}
+		return nil
+	})
+	var postErr *togo.PostHookError
+	if errors.As(err, &postErr) {
+		fmt.Println("togo:", postErr.Err)
+		return nil
+	}
+	return err
+}
+
+func init() {
+	rootCmd.AddCommand(gitCmd)
+	gitCmd.AddCommand(gitInstallHooksCmd)
+	gitCmd.AddCommand(gitBranchCmd)
+	gitCmd.AddCommand(gitSetupMergeCmd)
+	gitCmd.AddCommand(gitHookCmd)
+	gitInstallHooksCmd.Flags().Bool("force", false, "Replace existing hooks (they are kept as <hook>.bak)")
+}
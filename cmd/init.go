@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -12,8 +11,10 @@ import (
 
 var initCmd = &cobra.Command{
 	Use:   "init",
-	Short: "Initialize a project-local .togo file in the current directory",
-	Long:  "Create an empty .togo todos file in the current directory to use project-local storage.",
+	Short: "Initialize a project in the current directory",
+	Long: `Create a .togo file in the current directory to mark it as a project, so its
+todos are kept in todos.json next to it, and register the project so it can be
+used from anywhere with --project <name>.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		cwd, err := os.Getwd()
 		if err != nil {
@@ -23,23 +24,29 @@ var initCmd = &cobra.Command{
 		path := filepath.Join(cwd, ".togo")
 		if _, err := os.Stat(path); err == nil {
 			fmt.Println(".togo already exists in:", cwd)
-			return
+		} else {
+			if err := os.WriteFile(path, nil, 0644); err != nil {
+				fmt.Println("Error writing .togo:", err)
+				os.Exit(1)
+			}
+			fmt.Println("Initialized .togo in:", cwd)
 		}
 
-		tlist := model.NewTodoList()
-		data, err := json.Marshal(tlist)
+		name, _ := cmd.Flags().GetString("name")
+		project, added, err := model.RegisterProject(cwd, name)
 		if err != nil {
-			fmt.Println("Error creating initial data:", err)
-			os.Exit(1)
+			// The project works without being registered; only -P needs it.
+			fmt.Println("Warning: project not registered:", err)
+			fmt.Println("Register it under another name with 'togo projects add --name <name>'")
+			return
 		}
-		if err := os.WriteFile(path, data, 0644); err != nil {
-			fmt.Println("Error writing .togo:", err)
-			os.Exit(1)
+		if added {
+			fmt.Printf("Registered project %q; use 'togo -P %s' from anywhere\n", project.Name, project.Name)
 		}
-		fmt.Println("Initialized .togo in:", cwd)
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(initCmd)
+	initCmd.Flags().String("name", "", "name to register the project under (default: the directory name)")
 }
@@ -7,39 +7,51 @@ import (
 )
 
 var listCmd = &cobra.Command{
-	Use:   "list",
-	Short: "List all todos",
+	Use:         "list",
+	Short:       "List all todos",
+	Annotations: map[string]string{allSourcesAnnotation: "true"},
 	Long: `List all todos in a nice interactive UI.
 You can use:
 - list: to show active todos
 - list --archived: to show archived todos
-- list --all: to show both active and archived todos`,
+- list --all: to show both active and archived todos
+- list --snoozed: to show the todos hidden with 'togo snooze'
+- list --list backlog: to show only the todos in a named list
+- list --recursive: to show the todos of every project below the current
+  directory and the global list together, with a source column`,
 
 	Run: func(cmd *cobra.Command, args []string) {
-		todoList := loadTodoListOrExit()
+		store := openStoreOrExit(cmd)
+		tasks := loadTasksOrExit(store)
 
-		if checkEmptyTodoList(todoList, "No todos found. Add some todos with 'add' command.") {
+		if checkEmptyTodoList(tasks, "No todos found. Add some todos with 'add' command.") {
 			return
 		}
 
 		archivedFlag, _ := cmd.Flags().GetBool("archived")
 		allFlag, _ := cmd.Flags().GetBool("all")
-		sourceFlag, _ := cmd.Flags().GetString("source")
-
-		m := ui.NewTodoTable(todoList)
-		m.SetSourceLabel(sourceFlag)
+		snoozedFlag, _ := cmd.Flags().GetBool("snoozed")
+		listName, _ := cmd.Flags().GetString("list")
+		m := ui.NewTodoTable(store, tasks)
+		m.SetClientOptions(clientOptions()...)
+		if cmd.Flags().Changed("list") {
+			m.SetList(listName)
+		}
 
 		if archivedFlag {
 			m.SetShowArchivedOnly(true)
+		} else if snoozedFlag {
+			m.SetShowSnoozedOnly(true)
 		} else if allFlag {
 			m.SetShowAll(true)
 		} else {
 			m.SetShowActiveOnly(true)
 		}
 
-		_, err := tea.NewProgram(m, tea.WithAltScreen()).Run()
+		finalModel, err := tea.NewProgram(m, tea.WithAltScreen()).Run()
 		handleErrorAndExit(err, "Error running program:")
-		saveTodoListOrExit(todoList)
+		m = finalModel.(ui.TodoTableModel)
+		saveTableOrExit(cmd.Context(), &m)
 	},
 }
 
@@ -47,4 +59,8 @@ func init() {
 	rootCmd.AddCommand(listCmd)
 	listCmd.Flags().BoolP("archived", "a", false, "Show only archived todos")
 	listCmd.Flags().Bool("all", false, "Show all todos (both active and archived)")
+	listCmd.Flags().Bool("snoozed", false, "Show only snoozed todos")
+	listCmd.Flags().BoolP("recursive", "r", false, "Show the todos of every project below the current directory and the global list")
+	listCmd.Flags().StringP("list", "l", "", "Show only the todos in a named list")
+	_ = listCmd.RegisterFlagCompletionFunc("list", completeListNames)
 }
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/prime-run/togo/pkg/togo"
+	"github.com/spf13/cobra"
+)
+
+var listsCmd = &cobra.Command{
+	Use:   "lists",
+	Short: "Show the named lists in the current source",
+	Long: `Show the named lists in the current source with how many todos each holds.
+Todos added without --list belong to the "default" list. Archived todos are not counted.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		tasks := activeTasks(loadTasksOrExit(openClientOrExit()))
+		names := togo.ListNames(tasks)
+		width := 0
+		for _, name := range names {
+			width = max(width, len(name))
+		}
+		for _, name := range names {
+			pending, total := 0, 0
+			for _, task := range tasks {
+				if task.InList(name) {
+					total++
+					if !task.Completed {
+						pending++
+					}
+				}
+			}
+			fmt.Printf("%-*s  %d pending, %d total\n", width, name, pending, total)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(listsCmd)
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+
+	"github.com/prime-run/togo/mcp"
+	"github.com/spf13/cobra"
+)
+
+var mcpCmd = &cobra.Command{
+	Use:   "mcp",
+	Short: "Run a Model Context Protocol server over stdio",
+	Long: `Speak the Model Context Protocol (JSON-RPC over stdin/stdout) so AI assistants
+and editor tools can read and update your todos.
+
+Tools: list_todos, search_todos, add_todo, toggle_todo, archive_todo.
+Resources: togo://project/todos and togo://global/todos.
+
+Tools operate on the --source given to this command unless a call passes its
+own "source" argument. Changes run your lifecycle hooks as they do from the
+CLI. Configure your client to launch "togo mcp" from the project directory.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		srv := mcp.NewServer(sourceFlag, clientOptions()...)
+		handleErrorAndExit(srv.Serve(ctx, os.Stdin, os.Stdout), "Error running MCP server:")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(mcpCmd)
+}
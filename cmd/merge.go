@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/prime-run/togo/model"
+	"github.com/spf13/cobra"
+)
+
+var mergeDriverCmd = &cobra.Command{
+	Use:   "merge-driver <base> <ours> <theirs>",
+	Short: "Three-way merge of todo files, for use as a git merge driver",
+	Long: `Merge two diverged versions of a todos file against their common ancestor
+and write the result to <ours>, as git expects from a merge driver (%O %A %B).
+
+Tasks added on both sides are kept, colliding IDs on their side are
+renumbered, and edits to different fields of the same task are combined.
+When both sides changed the same field, our side wins.
+
+Register it in the current repository with 'togo git setup-merge'.`,
+	Args: cobra.ExactArgs(3),
+	Run: func(cmd *cobra.Command, args []string) {
+		lists := make([]*model.TodoList, 3)
+		for i, path := range args {
+			data, err := os.ReadFile(path)
+			handleErrorAndExit(err, "Error reading "+path+":")
+			lists[i], err = model.DecodeTodoList(data)
+			handleErrorAndExit(err, "Error parsing "+path+":")
+			handleErrorAndExit(lists[i].CheckWritable(), "Error merging "+path+":")
+		}
+		merged, err := model.MergeTodoLists(lists[0], lists[1], lists[2])
+		handleErrorAndExit(err, "Error merging todos:")
+		data, err := merged.Encode()
+		handleErrorAndExit(err, "Error encoding todos:")
+		if err := os.WriteFile(args[1], data, 0644); err != nil {
+			handleErrorAndExit(err, "Error writing "+args[1]+":")
+		}
+		fmt.Fprintf(os.Stderr, "togo: merged %d todos\n", len(merged.Todos))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(mergeDriverCmd)
+}
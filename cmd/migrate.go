@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/prime-run/togo/model"
+	"github.com/spf13/cobra"
+)
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Upgrade the todo file to the current schema version",
+	Long: `Upgrade the todo file of the selected source to the schema version of this
+togo binary. A copy of the original file is kept next to it as
+<file>.v<old-version>.bak.
+
+Files are also upgraded transparently whenever togo saves them; this command
+makes the upgrade explicit. With --check nothing is written and the command
+exits with status 1 if migrations are pending.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		path, err := model.ResolveTodoFilePath(TodoFileName, sourceFlag)
+		handleErrorAndExit(err, "Error resolving todo file:")
+		version, err := model.InspectTodoFile(path)
+		handleErrorAndExit(err, "Error reading "+path+":")
+		if version > model.SchemaVersion {
+			handleErrorAndExit(model.NewerSchemaError{Version: version}, "Error:")
+		}
+
+		pending := model.PendingMigrations(version)
+		if len(pending) == 0 {
+			fmt.Printf("%s is up to date (schema version %d)\n", path, version)
+			return
+		}
+		fmt.Printf("%s uses schema version %d, current is %d:\n", path, version, model.SchemaVersion)
+		for _, m := range pending {
+			fmt.Printf("  v%d -> v%d: %s\n", m.From, m.From+1, m.Description)
+		}
+
+		check, _ := cmd.Flags().GetBool("check")
+		if check {
+			os.Exit(1)
+		}
+		err = model.UpdateFile(path, func(*model.TodoList) error { return nil })
+		handleErrorAndExit(err, "Error migrating "+path+":")
+		fmt.Printf("Migrated %s (backup: %s.v%d.bak)\n", path, path, version)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+	migrateCmd.Flags().Bool("check", false, "Only report pending migrations; exit with status 1 if there are any")
+}
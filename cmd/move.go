@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/prime-run/togo/model"
+	"github.com/prime-run/togo/pkg/togo"
+	"github.com/spf13/cobra"
+)
+
+var moveCmd = &cobra.Command{
+	Use:   "move <title> --to <list|project|global|path>",
+	Short: "Move a todo to another list or source",
+	Long: `Move a todo to another named list in the same source, or to another source.
+
+--to takes a list name ("default" for the default list), "project", "global",
+or the path of a project directory (with a .togo file) or of an existing todo
+file. Moving to another source keeps the todo's status, list, creation time
+and links; it gets a new ID there.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		to, _ := cmd.Flags().GetString("to")
+		client := openClientOrExit()
+		todo := resolveTodoArgOrExit(loadTasksOrExit(client), args, "Select a todo to move")
+
+		dst, isSource := destinationOrExit(to)
+		if !isSource {
+			list := model.NormalizeListName(to)
+			if todo.InList(list) {
+				fmt.Printf("Todo \"%s\" is already in list \"%s\"\n", todo.Title, todo.ListName())
+				return
+			}
+			updateOrExit(client, func(l *togo.List) error {
+				return l.SetList(todo.ID, list)
+			})
+			fmt.Printf("Todo \"%s\" moved to list \"%s\"\n", todo.Title, model.Todo{List: list}.ListName())
+			return
+		}
+
+		moved, err := client.MoveTo(context.Background(), dst, todo.ID)
+		handleErrorAndExit(err, "Error moving todo:")
+		fmt.Printf("Todo \"%s\" moved to %s with ID: %d\n", todo.Title, dst.Path(), moved[0].ID)
+	},
+	ValidArgsFunction: completeTaskTitles(func(t togo.Task) bool { return true }),
+}
+
+var copyCmd = &cobra.Command{
+	Use:   "copy <title> --to <list|project|global|path>",
+	Short: "Copy a todo to another list or source",
+	Long: `Copy a todo to a named list in the same source, or to another source.
+--to is interpreted as for "togo move". The copy keeps the todo's fields but
+gets a new ID and UID.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		to, _ := cmd.Flags().GetString("to")
+		client := openClientOrExit()
+		todo := resolveTodoArgOrExit(loadTasksOrExit(client), args, "Select a todo to copy")
+
+		dst, isSource := destinationOrExit(to)
+		if !isSource {
+			var copied togo.Task
+			updateOrExit(client, func(l *togo.List) error {
+				task := todo
+				task.UID, task.List = "", model.NormalizeListName(to)
+				copied = l.Insert(task)
+				return nil
+			})
+			fmt.Printf("Todo \"%s\" copied to list \"%s\" with ID: %d\n", todo.Title, copied.ListName(), copied.ID)
+			return
+		}
+
+		copied, err := client.CopyTo(context.Background(), dst, todo.ID)
+		handleErrorAndExit(err, "Error copying todo:")
+		fmt.Printf("Todo \"%s\" copied to %s with ID: %d\n", todo.Title, dst.Path(), copied[0].ID)
+	},
+	ValidArgsFunction: completeTaskTitles(func(t togo.Task) bool { return true }),
+}
+
+// destinationOrExit interprets the --to flag of move and copy. Source names
+// and paths return a client for that source; anything else is a list name in
+// the current source.
+func destinationOrExit(to string) (*togo.Client, bool) {
+	to = strings.TrimSpace(to)
+	switch strings.ToLower(to) {
+	case togo.Project, togo.Global:
+		client, err := togo.Open(to, clientOptions()...)
+		handleErrorAndExit(err, "Error:")
+		return client, true
+	case togo.All:
+		handleErrorAndExit(fmt.Errorf("cannot move or copy to %q", to), "Error:")
+	}
+	if model.CheckListName(to) == nil {
+		return nil, false
+	}
+
+	path := to
+	if rest, ok := strings.CutPrefix(path, "~"); ok {
+		home, err := os.UserHomeDir()
+		handleErrorAndExit(err, "Error:")
+		path = filepath.Join(home, rest)
+	}
+	path, err := filepath.Abs(path)
+	handleErrorAndExit(err, "Error:")
+	st, err := os.Stat(path)
+	switch {
+	case err == nil && st.IsDir():
+		if _, err := os.Stat(filepath.Join(path, ".togo")); err != nil {
+			handleErrorAndExit(fmt.Errorf("%s is not a togo project (no .togo file); run 'togo init' there first", path), "Error:")
+		}
+		path = filepath.Join(path, TodoFileName)
+	case err == nil && filepath.Ext(path) == ".json":
+	case err == nil:
+		handleErrorAndExit(fmt.Errorf("%s is not a todo file (expected a .json file)", path), "Error:")
+	default:
+		handleErrorAndExit(fmt.Errorf("%s: no such project directory or todo file", path), "Error:")
+	}
+	return togo.OpenFile(path, clientOptions()...), true
+}
+
+func completeDestinations(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	names, _ := completeListNames(cmd, args, toComplete)
+	return append(names, togo.Project, togo.Global), cobra.ShellCompDirectiveNoFileComp
+}
+
+func init() {
+	for c, verb := range map[*cobra.Command]string{moveCmd: "move", copyCmd: "copy"} {
+		rootCmd.AddCommand(c)
+		c.Flags().String("to", "", "list, source (project or global) or path to "+verb+" the todo to")
+		_ = c.MarkFlagRequired("to")
+		_ = c.RegisterFlagCompletionFunc("to", completeDestinations)
+	}
+}
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/manifoldco/promptui"
+	"github.com/prime-run/togo/model"
+	"github.com/prime-run/togo/pkg/togo"
+	"github.com/prime-run/togo/report"
+	"github.com/spf13/cobra"
+)
+
+var planCmd = &cobra.Command{
+	Use:   "plan [title]",
+	Short: "Plan todos for today",
+	Long: `Put todos on the plan for today, a short list of what you commit to doing
+apart from the backlog. Without a title, pick todos from the backlog one at a
+time, most important first, until you choose "Done". --day plans for another
+day and --remove takes a todo off its plan.
+
+Unfinished todos planned for an earlier day roll over to today the next time
+the list is saved, and togo counts one deferral for each day they were pushed
+back, whether or not togo ran on those days.
+'togo today' shows the plan.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		dayFlag, _ := cmd.Flags().GetString("day")
+		remove, _ := cmd.Flags().GetBool("remove")
+		now := time.Now()
+		day, err := model.ParseWhen(dayFlag, now)
+		handleErrorAndExit(err, "Error:")
+		if day.IsZero() {
+			handleErrorAndExit(fmt.Errorf("--day needs a date"), "Error:")
+		}
+		client := openClientOrExit()
+		tasks := loadTasksOrExit(client)
+
+		var picked []togo.Task
+		switch {
+		case remove:
+			var planned []togo.Task
+			for _, task := range tasks {
+				if !task.PlannedFor.IsZero() && !task.Archived {
+					planned = append(planned, task)
+				}
+			}
+			if len(planned) == 0 {
+				fmt.Println("No todos are planned.")
+				return
+			}
+			picked = []togo.Task{resolveTodoArgOrExit(planned, args, "Select a todo to take off the plan")}
+			day = time.Time{}
+		case len(args) > 0:
+			picked = []togo.Task{resolveTodoArgOrExit(activeTasks(tasks), args, "Select a todo to plan")}
+		default:
+			picked = pickBacklogOrExit(tasks, day, now)
+		}
+		if len(picked) == 0 {
+			return
+		}
+		updateOrExit(client, func(l *togo.List) error {
+			for _, task := range picked {
+				if err := l.Plan(task.ID, day); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if remove {
+			fmt.Printf("Todo \"%s\" taken off the plan\n", picked[0].Title)
+			return
+		}
+		for _, task := range picked {
+			fmt.Printf("Todo \"%s\" planned for %s\n", task.Title, formatDue(day, now))
+		}
+	},
+	ValidArgsFunction: completeTaskTitles(func(t togo.Task) bool { return !t.Completed && !t.Archived }),
+}
+
+var todayCmd = &cobra.Command{
+	Use:   "today",
+	Short: "Show the todos planned for today",
+	Long: `Show the todos planned for today with 'togo plan', done or not, and how
+many days the open ones were deferred from an earlier day.`,
+	Annotations: map[string]string{allSourcesAnnotation: "true"},
+	Args:        cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		now := time.Now()
+		var planned []togo.Task
+		for _, task := range loadTasksOrExit(openStoreOrExit(cmd)) {
+			if task.PlannedToday(now) && !task.Archived {
+				planned = append(planned, task)
+			}
+		}
+		if len(planned) == 0 {
+			fmt.Println("Nothing planned for today. Pick todos from the backlog with 'togo plan'.")
+			return
+		}
+		done, width := 0, 0
+		for _, task := range planned {
+			if task.Completed {
+				done++
+			}
+			width = max(width, min(len([]rune(task.Title)), 40))
+		}
+		fmt.Printf("Today (%d/%d done)\n", done, len(planned))
+		for _, task := range planned {
+			check, note, deferrals := "[ ]", "", task.DeferralsAt(now)
+			if task.Completed {
+				check = "[x]"
+			} else if deferrals == 1 {
+				note = "deferred 1 day"
+			} else if deferrals > 1 {
+				note = fmt.Sprintf("deferred %d days", deferrals)
+			}
+			line := fmt.Sprintf("  %s %3d  %-*s  %s", check, task.ID, width, truncateTitle(task.Title, 40), note)
+			fmt.Println(strings.TrimRight(line, " "))
+		}
+	},
+}
+
+// pickBacklogOrExit lets the user choose open todos not yet planned for day,
+// most important first, until they pick "Done".
+func pickBacklogOrExit(tasks []togo.Task, day, now time.Time) []togo.Task {
+	today := day.Equal(report.StartOfDay(now))
+	var backlog []togo.Task
+	for _, r := range report.Rank(tasks, now, loadConfigOrExit().Scoring) {
+		if !r.Todo.PlannedOn(day) && !(today && r.Todo.RollsOver(now)) {
+			backlog = append(backlog, r.Todo)
+		}
+	}
+	if len(backlog) == 0 {
+		fmt.Println("The backlog is empty: every open todo is already planned.")
+		return nil
+	}
+	var picked []togo.Task
+	for len(backlog) > 0 {
+		items := []string{"Done"}
+		for _, task := range backlog {
+			items = append(items, fmt.Sprintf("%-3s %s", priorityMarker(task.Priority), task.Title))
+		}
+		prompt := promptui.Select{
+			Label: fmt.Sprintf("Plan for %s (%d picked)", formatDue(day, now), len(picked)),
+			Items: items,
+			Size:  10,
+		}
+		index, _, err := prompt.Run()
+		if err != nil {
+			fmt.Println("Operation cancelled")
+			os.Exit(0)
+		}
+		if index == 0 {
+			break
+		}
+		picked = append(picked, backlog[index-1])
+		backlog = append(backlog[:index-1:index-1], backlog[index:]...)
+	}
+	return picked
+}
+
+func init() {
+	rootCmd.AddCommand(planCmd)
+	rootCmd.AddCommand(todayCmd)
+	planCmd.Flags().StringP("day", "d", "today", "day to plan for (today, tomorrow, fri, 2006-01-02, ...)")
+	planCmd.Flags().BoolP("remove", "r", false, "take the todo off the plan")
+}
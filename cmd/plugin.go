@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/prime-run/togo/model"
+	"github.com/prime-run/togo/pkg/togo"
+	"github.com/spf13/cobra"
+)
+
+const pluginPrefix = "togo-"
+
+// discoverPlugins maps plugin names to the first togo-<name> executable found
+// on PATH, git-style.
+func discoverPlugins() map[string]string {
+	plugins := make(map[string]string)
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name, ok := strings.CutPrefix(entry.Name(), pluginPrefix)
+			if !ok {
+				continue
+			}
+			if runtime.GOOS == "windows" {
+				name, ok = strings.CutSuffix(name, ".exe")
+				if !ok {
+					continue
+				}
+			}
+			if name == "" || plugins[name] != "" {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			info, err := os.Stat(path)
+			if err != nil || !info.Mode().IsRegular() {
+				continue
+			}
+			if runtime.GOOS != "windows" && info.Mode().Perm()&0o111 == 0 {
+				continue
+			}
+			plugins[name] = path
+		}
+	}
+	return plugins
+}
+
+// registerPlugins adds a command for every plugin whose name does not clash
+// with a built-in one, so plugins show up in help and shell completion.
+func registerPlugins() {
+	plugins := discoverPlugins()
+	var names []string
+	for name := range plugins {
+		if name == "help" || name == "completion" || strings.HasPrefix(name, "__") {
+			continue
+		}
+		if found, _, err := rootCmd.Find([]string{name}); err == nil && found != rootCmd {
+			continue
+		}
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return
+	}
+	slices.Sort(names)
+
+	rootCmd.AddGroup(
+		&cobra.Group{ID: "builtin", Title: "Available Commands:"},
+		&cobra.Group{ID: "plugins", Title: "Plugin Commands:"},
+	)
+	for _, c := range rootCmd.Commands() {
+		c.GroupID = "builtin"
+	}
+	rootCmd.SetHelpCommandGroupID("builtin")
+	rootCmd.SetCompletionCommandGroupID("builtin")
+
+	for _, name := range names {
+		path := plugins[name]
+		rootCmd.AddCommand(&cobra.Command{
+			Use:                name,
+			Short:              fmt.Sprintf("Plugin (%s)", path),
+			GroupID:            "plugins",
+			DisableFlagParsing: true,
+			Run: func(cmd *cobra.Command, args []string) {
+				runPlugin(path, cmd.Name())
+			},
+		})
+	}
+}
+
+// needsPlugins reports whether the command line needs the plugin commands:
+// to run a command togo does not know, or to list them in help and shell
+// completion. Other commands skip searching PATH.
+func needsPlugins(args []string) bool {
+	cmd, _, err := rootCmd.Find(args)
+	if err != nil {
+		return true
+	}
+	switch cmd.Name() {
+	case "help", "completion", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+		return true
+	}
+	return slices.ContainsFunc(args, func(arg string) bool { return arg == "-h" || arg == "--help" })
+}
+
+// commandIndex returns the index in args of the first argument that is
+// neither one of togo's persistent flags nor the value of one, or -1.
+func commandIndex(args []string) int {
+	flags := rootCmd.PersistentFlags()
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			if i+1 < len(args) {
+				return i + 1
+			}
+			return -1
+		case strings.HasPrefix(arg, "--"):
+			if f := flags.Lookup(arg[2:]); f != nil && f.NoOptDefVal == "" {
+				i++
+			}
+		case len(arg) == 2 && arg[0] == '-':
+			if f := flags.ShorthandLookup(arg[1:]); f != nil && f.NoOptDefVal == "" {
+				i++
+			}
+		case strings.HasPrefix(arg, "-"):
+		default:
+			return i
+		}
+	}
+	return -1
+}
+
+// pluginArgs splits the command line around the plugin name: flags given to
+// togo before it (such as --source) are applied here, everything after it is
+// handed to the plugin untouched.
+func pluginArgs(name string) ([]string, error) {
+	args := os.Args[1:]
+	i := commandIndex(args)
+	if i < 0 || args[i] != name {
+		return nil, fmt.Errorf("plugin %q not found on the command line", name)
+	}
+	if err := rootCmd.PersistentFlags().Parse(args[:i]); err != nil {
+		return nil, err
+	}
+	return args[i+1:], applyPersistentFlags()
+}
+
+func runPlugin(path, name string) {
+	args, err := pluginArgs(name)
+	handleErrorAndExit(err, "Error:")
+
+	client := openClientOrExit()
+	sourcePath := client.Path()
+	todoList, err := model.LoadTodoListFile(sourcePath)
+	handleErrorAndExit(err, "Error loading todos:")
+	snapshot, err := todoList.Encode()
+	handleErrorAndExit(err, "Error encoding todos:")
+
+	// A project source falls back to the global list when no .togo file is
+	// found, so tell the plugin what the client actually resolved to.
+	source, projectRoot := togo.Global, ""
+	if dir := filepath.Dir(sourcePath); client.Source() == togo.Project {
+		if _, err := os.Stat(filepath.Join(dir, ".togo")); err == nil {
+			source, projectRoot = togo.Project, dir
+		}
+	}
+	self, _ := os.Executable()
+
+	plugin := exec.Command(path, args...)
+	plugin.Stdin = bytes.NewReader(snapshot)
+	plugin.Stdout = os.Stdout
+	plugin.Stderr = os.Stderr
+	plugin.Env = append(os.Environ(),
+		"TOGO_SOURCE="+source,
+		"TOGO_SOURCE_PATH="+sourcePath,
+		"TOGO_PROJECT_ROOT="+projectRoot,
+		"TOGO_BIN="+self,
+	)
+	if err := plugin.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.ExitCode())
+		}
+		handleErrorAndExit(err, "Error running plugin:")
+	}
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prime-run/togo/model"
+	"github.com/prime-run/togo/report"
+	"github.com/spf13/cobra"
+)
+
+var pomodorosCmd = &cobra.Command{
+	Use:   "pomodoros",
+	Short: "Summarize the pomodoros finished on each todo",
+	Long: `List the todos pomodoros were finished on in the TUI's pomodoro mode, with
+today's count, the count for the current week and the total. Use --source all
+to include every project below the current directory and the global list.`,
+	Annotations: map[string]string{allSourcesAnnotation: "true"},
+	Args:        cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		todos := loadTasksOrExit(openStoreOrExit(cmd))
+		now := time.Now()
+		total := report.SummarizePomodoros(todos, time.Time{}, now.Add(time.Second))
+		if len(total) == 0 {
+			fmt.Println("No pomodoros finished yet. Press 'p' on a task in the TUI to start one.")
+			return
+		}
+		day := pomodoroCounts(report.SummarizePomodoros(todos, report.StartOfDay(now), now.Add(time.Second)))
+		week := pomodoroCounts(report.SummarizePomodoros(todos, report.StartOfWeek(now), now.Add(time.Second)))
+
+		width := len("Task")
+		for _, t := range total {
+			width = max(width, len([]rune(t.Todo.Title)))
+		}
+		fmt.Printf("%-*s  %5s  %5s  %5s  %s\n", width, "Task", "Today", "Week", "Total", "Focus")
+		sum := 0
+		for _, t := range total {
+			fmt.Printf("%-*s  %5d  %5d  %5d  %s\n", width, t.Todo.Title,
+				day[t.Todo.UID], week[t.Todo.UID], t.Count, model.FormatDuration(t.Focus))
+			sum += t.Count
+		}
+		fmt.Printf("\n%d pomodoros, %d today\n", sum, sumPomodoroCounts(day))
+	},
+}
+
+func pomodoroCounts(summary []report.TaskPomodoros) map[string]int {
+	byUID := make(map[string]int, len(summary))
+	for _, t := range summary {
+		byUID[t.Todo.UID] = t.Count
+	}
+	return byUID
+}
+
+func sumPomodoroCounts(byUID map[string]int) int {
+	n := 0
+	for _, c := range byUID {
+		n += c
+	}
+	return n
+}
+
+func init() {
+	rootCmd.AddCommand(pomodorosCmd)
+}
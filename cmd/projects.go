@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/prime-run/togo/model"
+	"github.com/prime-run/togo/pkg/togo"
+	"github.com/spf13/cobra"
+)
+
+var projectsCmd = &cobra.Command{
+	Use:   "projects",
+	Short: "List the registered projects",
+	Long: `List the projects registered with 'togo init' or 'togo projects add', with
+their pending and total todo counts. The current project is marked with *.
+
+Any command can operate on a registered project from anywhere with
+--project/-P <name>, e.g. 'togo -P api list'.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		projects, err := model.LoadProjects()
+		handleErrorAndExit(err, "Error loading projects:")
+		if len(projects) == 0 {
+			fmt.Println("No projects registered. Run 'togo init' or 'togo projects add' in a project.")
+			return
+		}
+		current, _ := model.FindProjectRoot()
+		width := 0
+		for _, p := range projects {
+			width = max(width, len(p.Name))
+		}
+		for _, p := range projects {
+			marker := " "
+			if p.Root == current {
+				marker = "*"
+			}
+			status := "missing"
+			if _, err := os.Stat(filepath.Join(p.Root, ".togo")); err == nil {
+				tasks, err := togo.OpenFile(filepath.Join(p.Root, TodoFileName)).Tasks(cmd.Context())
+				if err != nil {
+					status = "unreadable: " + err.Error()
+				} else {
+					active := activeTasks(tasks)
+					pending := 0
+					for _, task := range active {
+						if !task.Completed {
+							pending++
+						}
+					}
+					status = fmt.Sprintf("%d pending, %d total", pending, len(active))
+				}
+			}
+			fmt.Printf("%s %-*s  %s  (%s)\n", marker, width, p.Name, p.Root, status)
+		}
+	},
+}
+
+var projectsAddCmd = &cobra.Command{
+	Use:   "add [dir]",
+	Short: "Register a project",
+	Long: `Register the project in dir, or the current project, so it can be used from
+anywhere with --project <name>. The directory must contain a .togo file.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var root string
+		if len(args) == 1 {
+			root = args[0]
+			if _, err := os.Stat(filepath.Join(root, ".togo")); err != nil {
+				fmt.Printf("Error: %s has no .togo file. Run 'togo init' there first.\n", root)
+				os.Exit(1)
+			}
+		} else {
+			var ok bool
+			if root, ok = model.FindProjectRoot(); !ok {
+				fmt.Println("Error: no .togo file found. Run 'togo init' in your project root first.")
+				os.Exit(1)
+			}
+		}
+		name, _ := cmd.Flags().GetString("name")
+		project, added, err := model.RegisterProject(root, name)
+		handleErrorAndExit(err, "Error registering project:")
+		if !added {
+			fmt.Printf("Project already registered as %q\n", project.Name)
+			return
+		}
+		fmt.Printf("Registered project %q (%s)\n", project.Name, project.Root)
+	},
+}
+
+var projectsRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Unregister a project",
+	Long:  `Remove a project from the registry. Its .togo and todo files are left untouched.`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		handleErrorAndExit(model.UnregisterProject(args[0]), "Error:")
+		fmt.Printf("Project %q unregistered\n", args[0])
+	},
+	ValidArgsFunction: completeProjectNames,
+}
+
+func init() {
+	rootCmd.AddCommand(projectsCmd)
+	projectsCmd.AddCommand(projectsAddCmd, projectsRemoveCmd)
+	projectsAddCmd.Flags().String("name", "", "name to register the project under (default: the directory name)")
+}
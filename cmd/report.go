@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prime-run/togo/model"
+	"github.com/prime-run/togo/report"
+	"github.com/spf13/cobra"
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Reports on the todo list",
+}
+
+var burndownCmd = &cobra.Command{
+	Use:   "burndown",
+	Short: "Chart the work remaining day by day",
+	Long: `Draw a burndown chart of the work remaining at the end of each day since
+--since (a date such as 2026-10-01, or a span such as 14d or 2w; default 14d),
+followed by the estimated, completed and remaining work.
+
+Work is measured in points when any todo is estimated in points, otherwise in
+hours when any is estimated in time, otherwise in tasks; --by picks the unit.
+Unestimated todos count as zero points or hours. Set estimates with
+'togo add --estimate' or 'togo estimate'.`,
+	Annotations: map[string]string{allSourcesAnnotation: "true"},
+	Args:        cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		sinceFlag, _ := cmd.Flags().GetString("since")
+		by, _ := cmd.Flags().GetString("by")
+		now := time.Now()
+		since, err := parseSince(sinceFlag, now)
+		handleErrorAndExit(err, "Error:")
+
+		b := report.BuildBurndown(loadTasksOrExit(openStoreOrExit(cmd)), since, now)
+		unit := b.DefaultUnit()
+		switch report.Unit(by) {
+		case "":
+		case report.UnitPoints, report.UnitHours, report.UnitTasks:
+			unit = report.Unit(by)
+		default:
+			handleErrorAndExit(fmt.Errorf("invalid unit %q (use points, hours or tasks)", by), "Error:")
+		}
+
+		fmt.Printf("Burndown since %s, in %s\n\n", b.From.Format("Mon 2006-01-02"), unit)
+		if b.Estimated.Tasks == 0 {
+			fmt.Println("No todos in this period.")
+			return
+		}
+		for _, line := range burndownChart(b.Days, unit, 10) {
+			fmt.Println(line)
+		}
+		remaining := b.Estimated
+		remaining.Points -= b.Completed.Points
+		remaining.Time -= b.Completed.Time
+		remaining.Tasks -= b.Completed.Tasks
+		fmt.Println()
+		fmt.Printf("Estimated  %s\n", formatWork(b.Estimated))
+		fmt.Printf("Completed  %s\n", formatWork(b.Completed))
+		fmt.Printf("Remaining  %s\n", formatWork(remaining))
+		if b.Unestimated > 0 {
+			fmt.Printf("\nTodos without an estimate: %d of %d\n", b.Unestimated, b.Estimated.Tasks)
+		}
+		if b.Unknown > 0 {
+			fmt.Printf("Completed todos left out for lack of a completion time: %d\n", b.Unknown)
+		}
+	},
+}
+
+// burndownChart draws the remaining work of each day as a column of height
+// rows, with a value axis on the left and day-of-month labels below.
+func burndownChart(days []report.BurndownDay, unit report.Unit, height int) []string {
+	top := 0.0
+	for _, d := range days {
+		top = max(top, d.Remaining.In(unit))
+	}
+	if top == 0 {
+		top = 1
+	}
+	colWidth := 2
+	if len(days) > 40 {
+		colWidth = 1
+	}
+	labels := map[int]string{
+		height - 1:       formatAmount(top, unit),
+		(height - 1) / 2: formatAmount(top*float64((height-1)/2+1)/float64(height), unit),
+	}
+	labelWidth := len(formatAmount(0, unit))
+	for _, l := range labels {
+		labelWidth = max(labelWidth, len(l))
+	}
+
+	blocks := []rune(" ▁▂▃▄▅▆▇█")
+	var lines []string
+	for row := height - 1; row >= 0; row-- {
+		var b strings.Builder
+		if l, ok := labels[row]; ok {
+			fmt.Fprintf(&b, "%*s ┤", labelWidth, l)
+		} else {
+			fmt.Fprintf(&b, "%*s │", labelWidth, "")
+		}
+		for _, d := range days {
+			level := d.Remaining.In(unit) / top * float64(height)
+			fill := math.Round((level - float64(row)) * 8)
+			cell := blocks[int(max(0, min(8, fill)))]
+			b.WriteString(strings.Repeat(string(cell), colWidth))
+		}
+		lines = append(lines, strings.TrimRight(b.String(), " "))
+	}
+	axis := fmt.Sprintf("%*s └", labelWidth, formatAmount(0, unit)) + strings.Repeat("─", len(days)*colWidth)
+	dates := []rune(strings.Repeat(" ", labelWidth+2+len(days)*colWidth+2))
+	next := 0
+	for i, d := range days {
+		if x := i * colWidth; x >= next {
+			copy(dates[labelWidth+2+x:], []rune(d.Date.Format("02")))
+			next = x + 4
+		}
+	}
+	return append(lines, axis, strings.TrimRight(string(dates), " "))
+}
+
+func formatAmount(v float64, unit report.Unit) string {
+	switch unit {
+	case report.UnitHours:
+		if v == 0 {
+			return "0h"
+		}
+		return model.FormatDuration(time.Duration(v * float64(time.Hour)).Round(time.Minute))
+	case report.UnitPoints:
+		return strconv.FormatFloat(math.Round(v*10)/10, 'f', -1, 64)
+	}
+	return strconv.Itoa(int(math.Round(v)))
+}
+
+func formatWork(w report.Work) string {
+	var parts []string
+	if w.Points != 0 {
+		parts = append(parts, model.FormatPoints(w.Points))
+	}
+	if w.Time != 0 {
+		parts = append(parts, model.FormatDuration(w.Time))
+	}
+	tasks := fmt.Sprintf("%d todos", w.Tasks)
+	if w.Tasks == 1 {
+		tasks = "1 todo"
+	}
+	if len(parts) == 0 {
+		return tasks
+	}
+	return strings.Join(parts, ", ") + " (" + tasks + ")"
+}
+
+// parseSince reads a date (2006-01-02) or a span back from now in days or
+// weeks (14d, 2w) or as a Go duration (36h).
+func parseSince(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.ParseInLocation("2006-01-02", s, now.Location()); err == nil {
+		return t, nil
+	}
+	for suffix, days := range map[string]int{"d": 1, "w": 7} {
+		if n, err := strconv.Atoi(strings.TrimSuffix(s, suffix)); err == nil && strings.HasSuffix(s, suffix) && n >= 0 {
+			return report.StartOfDay(now).AddDate(0, 0, -n*days), nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %q: use a date (2006-01-02) or a span (14d, 2w)", s)
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+	reportCmd.AddCommand(burndownCmd)
+	burndownCmd.Flags().String("since", "14d", "start of the chart: a date (2006-01-02) or a span back from today (14d, 2w)")
+	burndownCmd.Flags().String("by", "", "unit of work: points, hours or tasks (default: from the estimates)")
+	_ = burndownCmd.RegisterFlagCompletionFunc("by", cobra.FixedCompletions(
+		[]string{string(report.UnitPoints), string(report.UnitHours), string(report.UnitTasks)}, cobra.ShellCompDirectiveNoFileComp))
+}
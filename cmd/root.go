@@ -1,9 +1,13 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 
+	"github.com/prime-run/togo/model"
 	"github.com/prime-run/togo/ui"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -12,48 +16,90 @@ import (
 
 var TodoFileName = "todos.json"
 var sourceFlag string = "project"
+var projectFlag string
+
+var errSourceAllViewOnly = errors.New("--source all is only supported by commands that view tasks (togo, togo list, togo timesheet, togo pomodoros, togo stats, togo report, togo agenda, togo next)")
+
+// allSourcesAnnotation marks commands that accept --source all.
+const allSourcesAnnotation = "togo/all-sources"
 
 var rootCmd = &cobra.Command{
-	Use:   "togo",
-	Short: "A simple todo application",
-	Long:  `A simple todo application that lets you manage your tasks from the terminal.`,
+	Use:         "togo",
+	Short:       "A simple todo application",
+	Long:        `A simple todo application that lets you manage your tasks from the terminal.`,
+	Annotations: map[string]string{allSourcesAnnotation: "true"},
 	Run: func(cmd *cobra.Command, args []string) {
-		todoList := loadTodoListOrExit()
-
-		tableModel := ui.NewTodoTable(todoList)
-		tableModel.SetSource(sourceFlag, TodoFileName)
-		_, err := tea.NewProgram(tableModel, tea.WithAltScreen()).Run()
+		store := openStoreOrExit(cmd)
+		tableModel := ui.NewTodoTable(store, loadTasksOrExit(store))
+		tableModel.SetClientOptions(clientOptions()...)
+		finalModel, err := tea.NewProgram(tableModel, tea.WithAltScreen()).Run()
 		handleErrorAndExit(err, "Error running program:")
 
-		finalSource := tableModel.GetSourceLabel()
-		finalList := tableModel.GetTodoList()
-		if err := finalList.SaveWithSource(TodoFileName, finalSource); err != nil {
-			handleErrorAndExit(err, "Error saving todos:")
-		}
+		tableModel = finalModel.(ui.TodoTableModel)
+		saveTableOrExit(cmd.Context(), &tableModel)
 	},
 }
 
 func Execute() error {
+	if needsPlugins(os.Args[1:]) {
+		registerPlugins()
+	}
 	return rootCmd.Execute()
 }
 
+func normalizeSourceFlag() error {
+	s := strings.ToLower(strings.TrimSpace(sourceFlag))
+	switch s {
+	case "project", "global", "all":
+		sourceFlag = s
+		return nil
+	default:
+		return fmt.Errorf("invalid value for --source: %q (must be 'project', 'global' or 'all')", sourceFlag)
+	}
+}
+
+// applyPersistentFlags validates --source and points project sources at the
+// registered project named by --project, if any.
+func applyPersistentFlags() error {
+	if err := normalizeSourceFlag(); err != nil {
+		return err
+	}
+	if projectFlag == "" {
+		return nil
+	}
+	if sourceFlag == "global" {
+		return errors.New("--project cannot be combined with --source global")
+	}
+	project, err := model.LookupProject(projectFlag)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(filepath.Join(project.Root, ".togo")); err != nil {
+		return fmt.Errorf("project %q has no .togo file in %s; was it moved? (see 'togo projects')", project.Name, project.Root)
+	}
+	model.UseProject(project.Root)
+	return nil
+}
+
 func init() {
 
-	rootCmd.PersistentFlags().StringVarP(&sourceFlag, "source", "s", "project", "todo source: project or global")
+	rootCmd.PersistentFlags().StringVarP(&sourceFlag, "source", "s", "project", "todo source: project, global or all (every project below the current directory plus global)")
+	rootCmd.PersistentFlags().StringVarP(&projectFlag, "project", "P", "", "use the registered project with this name instead of the current one (see 'togo projects')")
+	rootCmd.Flags().BoolP("recursive", "r", false, "show the tasks of every project below the current directory and the global list (same as --source all)")
 
 	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
-		s := strings.ToLower(strings.TrimSpace(sourceFlag))
-		switch s {
-		case "project", "global":
-			sourceFlag = s
-			return nil
-		default:
-			return fmt.Errorf("invalid value for --source: %q (must be 'project' or 'global')", sourceFlag)
+		if err := applyPersistentFlags(); err != nil {
+			return err
+		}
+		if sourceFlag == "all" && cmd.Annotations[allSourcesAnnotation] == "" {
+			return errSourceAllViewOnly
 		}
+		return nil
 	}
 
 	_ = rootCmd.RegisterFlagCompletionFunc("source", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		return []string{"project", "global"}, cobra.ShellCompDirectiveNoFileComp
+		return []string{"project", "global", "all"}, cobra.ShellCompDirectiveNoFileComp
 	})
+	_ = rootCmd.RegisterFlagCompletionFunc("project", completeProjectNames)
 
 }
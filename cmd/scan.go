@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/prime-run/togo/model"
+	"github.com/prime-run/togo/pkg/togo"
+	"github.com/prime-run/togo/scan"
+	"github.com/spf13/cobra"
+)
+
+var scanCmd = &cobra.Command{
+	Use:   "scan [paths...]",
+	Short: "Sync TODO/FIXME/HACK comments from source code into tasks",
+	Long: `Scan the project containing the closest .togo file for TODO, FIXME and HACK
+comments and keep a task for each one, linked to its file:line. The tag must
+start the comment ("// TODO: ..."); a tag later in the text is not a task. The
+tasks always go to the project's list.
+
+Files ignored by .gitignore are skipped. Comments that disappear from the
+code mark their task as completed; comments that come back reopen it.
+
+Extra comment syntaxes can be given with --rule:
+  --rule .tpl=##        line comments starting with ##
+  --rule .tpl={{/*,*/}} block comments between {{/* and */}}`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if sourceFlag != togo.Project {
+			handleErrorAndExit(fmt.Errorf("scan links tasks to files in the project, so it only works with --source project"), "Error:")
+		}
+		root, ok := model.FindProjectRoot()
+		if !ok {
+			fmt.Println("Error: no .togo file found. Run 'togo init' in your project root first.")
+			os.Exit(1)
+		}
+
+		rules, _ := cmd.Flags().GetStringArray("rule")
+		for _, r := range rules {
+			key, rule, err := parseCommentRule(r)
+			handleErrorAndExit(err, "Error:")
+			scan.Register(key, rule)
+		}
+
+		var scopes []string
+		for _, p := range args {
+			rel, err := scan.Rel(root, p)
+			handleErrorAndExit(err, "Error:")
+			scopes = append(scopes, rel)
+		}
+
+		items, err := scan.Walk(root, args)
+		handleErrorAndExit(err, "Error scanning project:")
+
+		client, err := togo.Open(togo.Project, clientOptions()...)
+		handleErrorAndExit(err, "Error:")
+		changes := scan.Plan(loadTasksOrExit(client), items, scopes)
+
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		counts := make(map[scan.ChangeKind]int)
+		for _, c := range changes {
+			counts[c.Kind]++
+			if c.Kind == scan.ChangeMoved {
+				fmt.Printf("~ %-9s %s (%s -> %s)\n", c.Kind, c.Title, c.From, c.Location)
+				continue
+			}
+			fmt.Printf("%s %-9s %s (%s)\n", changeMarker(c.Kind), c.Kind, c.Title, c.Location)
+		}
+
+		summary := fmt.Sprintf("%d comments found: %d added, %d moved, %d completed, %d reopened",
+			len(items), counts[scan.ChangeAdded], counts[scan.ChangeMoved], counts[scan.ChangeCompleted], counts[scan.ChangeReopened])
+		if dryRun {
+			fmt.Println("Dry run, nothing saved.", summary)
+			return
+		}
+		if len(changes) > 0 {
+			updateOrExit(client, func(l *togo.List) error {
+				return scan.Apply(l, changes)
+			})
+		}
+		fmt.Println(summary)
+	},
+}
+
+func changeMarker(kind scan.ChangeKind) string {
+	switch kind {
+	case scan.ChangeAdded:
+		return "+"
+	case scan.ChangeCompleted:
+		return "✓"
+	case scan.ChangeReopened:
+		return "↺"
+	default:
+		return "~"
+	}
+}
+
+func parseCommentRule(s string) (string, scan.CommentRule, error) {
+	key, syntax, ok := strings.Cut(s, "=")
+	if !ok || key == "" || syntax == "" {
+		return "", scan.CommentRule{}, fmt.Errorf("invalid --rule %q (expected EXT=PREFIX or EXT=START,END)", s)
+	}
+	if start, end, ok := strings.Cut(syntax, ","); ok {
+		return key, scan.CommentRule{BlockStart: start, BlockEnd: end}, nil
+	}
+	return key, scan.CommentRule{Line: []string{syntax}}, nil
+}
+
+func init() {
+	rootCmd.AddCommand(scanCmd)
+	scanCmd.Flags().Bool("dry-run", false, "Show what would change without saving")
+	scanCmd.Flags().StringArray("rule", nil, "Extra comment syntax for a file extension or name (EXT=PREFIX or EXT=START,END)")
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/prime-run/togo/server"
+	"github.com/spf13/cobra"
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve a local HTTP/JSON API for your todos",
+	Long: `Start a local HTTP server exposing your todos as a JSON API, for dashboards
+and editor plugins. Every request reads and writes the todo file directly, so
+changes made from the CLI or TUI are picked up immediately.
+
+Requests operate on the --source given to this command unless they pass
+?source=project|global. When --token (or $TOGO_TOKEN) is set, requests must
+send "Authorization: Bearer <token>"; a token is required to listen on an
+address other than localhost. Without one, only requests for localhost are
+served. Requests from web pages on other origins are refused, and request
+bodies must be sent as application/json. The API is described at
+/openapi.json.
+
+Changes run your lifecycle hooks as they do from the CLI: a change a hook
+rejects fails with 422 and saves nothing, and a failing post-add or
+post-complete hook is reported in a Warning header of the saved change.
+
+Live clients can follow changes (including edits made by other processes) as
+server-sent events on /events or as JSON messages on the /ws websocket.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		addr, _ := cmd.Flags().GetString("addr")
+		token, _ := cmd.Flags().GetString("token")
+		if token == "" {
+			token = os.Getenv("TOGO_TOKEN")
+		}
+		host, _, err := net.SplitHostPort(addr)
+		handleErrorAndExit(err, "Error:")
+		if token == "" && !server.IsLoopback(host) {
+			handleErrorAndExit(fmt.Errorf("listening on %s needs a --token (or $TOGO_TOKEN), as anyone on the network could reach it", addr), "Error:")
+		}
+		srv := server.New(sourceFlag, token, clientOptions()...)
+		handleErrorAndExit(srv.Watch(context.Background(), time.Second), "Error watching todo files:")
+		fmt.Printf("Serving togo API on http://%s (source: %s)\n", addr, sourceFlag)
+		handleErrorAndExit(http.ListenAndServe(addr, srv), "Error running server:")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+	serveCmd.Flags().String("addr", "127.0.0.1:7777", "Address to listen on")
+	serveCmd.Flags().String("token", "", "Require this bearer token on every request (default $TOGO_TOKEN)")
+}
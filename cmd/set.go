@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/prime-run/togo/model"
+	"github.com/prime-run/togo/pkg/togo"
+	"github.com/spf13/cobra"
+)
+
+var setCmd = &cobra.Command{
+	Use:   "set [title]",
+	Short: "Set the due date, priority, blockers or estimate of a todo",
+	Long: `Change the planning fields of a todo:
+
+  --due        today, tomorrow, a weekday (fri), next week, 3d, 2w, 2026-10-30,
+               optionally followed by a time (fri 15:00); "none" clears it
+  --priority   high, medium, low or none
+  --blocked-by a todo this one waits for (repeatable); --unblock clears them
+  --estimate   points (3) or time (2h); "none" clears it
+
+Without a title you are asked to pick the todo.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		client := openClientOrExit()
+		tasks := loadTasksOrExit(client)
+		todo := resolveTodoArgOrExit(tasks, args, "Select a todo to change")
+		plan := planningFlagsOrExit(cmd, tasks)
+		estimate, setEstimate := togo.Estimate{}, cmd.Flags().Changed("estimate")
+		if setEstimate {
+			flag, _ := cmd.Flags().GetString("estimate")
+			var err error
+			estimate, err = togo.ParseEstimate(flag)
+			handleErrorAndExit(err, "Error:")
+		}
+		unblock, _ := cmd.Flags().GetBool("unblock")
+		if !plan.any() && !setEstimate && !unblock {
+			fmt.Println("Error: nothing to set; use --due, --priority, --blocked-by, --unblock or --estimate")
+			os.Exit(1)
+		}
+
+		var saved togo.Task
+		updateOrExit(client, func(l *togo.List) error {
+			if plan.setDue {
+				if err := l.SetDue(todo.ID, plan.due); err != nil {
+					return err
+				}
+			}
+			if plan.setPriority {
+				if err := l.SetPriority(todo.ID, plan.priority); err != nil {
+					return err
+				}
+			}
+			if unblock || plan.setBlockers {
+				ids := make([]int, len(plan.blockers))
+				for i, b := range plan.blockers {
+					ids[i] = b.ID
+				}
+				if err := l.SetBlockedBy(todo.ID, ids...); err != nil {
+					return err
+				}
+			}
+			if setEstimate {
+				if err := l.SetEstimate(todo.ID, estimate); err != nil {
+					return err
+				}
+			}
+			saved, _ = l.Task(todo.ID)
+			return nil
+		})
+		fmt.Printf("Todo \"%s\" updated\n", saved.Title)
+		printPlanning(saved, tasks)
+	},
+	ValidArgsFunction: completeTaskTitles(func(t togo.Task) bool { return !t.Archived }),
+}
+
+// planning holds the planning flags given to add or set.
+type planning struct {
+	due                              time.Time
+	priority                         model.Priority
+	blockers                         []model.Todo
+	setDue, setPriority, setBlockers bool
+}
+
+func (p planning) any() bool {
+	return p.setDue || p.setPriority || p.setBlockers
+}
+
+func addPlanningFlags(c *cobra.Command) {
+	c.Flags().StringP("due", "d", "", "due date: today, tomorrow, fri, 3d, 2026-10-30, optionally with a time (fri 15:00)")
+	c.Flags().StringP("priority", "p", "", "priority: high, medium, low or none")
+	c.Flags().StringArray("blocked-by", nil, "title or ID of a todo this one waits for (repeatable)")
+	_ = c.RegisterFlagCompletionFunc("priority", cobra.FixedCompletions(
+		[]string{"high", "medium", "low", "none"}, cobra.ShellCompDirectiveNoFileComp))
+	_ = c.RegisterFlagCompletionFunc("blocked-by", completeTaskTitles(func(t togo.Task) bool { return !t.Archived && !t.Completed }))
+}
+
+// planningFlagsOrExit parses the flags added by addPlanningFlags, looking up
+// blockers among tasks.
+func planningFlagsOrExit(cmd *cobra.Command, tasks []model.Todo) planning {
+	var p planning
+	var err error
+	if p.setDue = cmd.Flags().Changed("due"); p.setDue {
+		flag, _ := cmd.Flags().GetString("due")
+		p.due, err = model.ParseWhen(flag, time.Now())
+		handleErrorAndExit(err, "Error:")
+	}
+	if p.setPriority = cmd.Flags().Changed("priority"); p.setPriority {
+		flag, _ := cmd.Flags().GetString("priority")
+		p.priority, err = model.ParsePriority(flag)
+		handleErrorAndExit(err, "Error:")
+	}
+	if p.setBlockers = cmd.Flags().Changed("blocked-by"); p.setBlockers {
+		refs, _ := cmd.Flags().GetStringArray("blocked-by")
+		for _, ref := range refs {
+			p.blockers = append(p.blockers, resolveTodoArgOrExit(tasks, []string{ref}, "Select the todo it waits for"))
+		}
+	}
+	return p
+}
+
+// printPlanning prints the planning fields a todo has.
+func printPlanning(todo model.Todo, tasks []model.Todo) {
+	now := time.Now()
+	if !todo.Due.IsZero() {
+		fmt.Printf("Due: %s\n", formatDue(todo.Due, now))
+	}
+	if todo.Priority != model.PriorityNone {
+		fmt.Printf("Priority: %s\n", todo.Priority)
+	}
+	if len(todo.BlockedBy) > 0 {
+		var titles []string
+		for _, uid := range todo.BlockedBy {
+			for _, t := range tasks {
+				if t.UID == uid {
+					titles = append(titles, fmt.Sprintf("%q", t.Title))
+				}
+			}
+		}
+		fmt.Printf("Blocked by: %s\n", strings.Join(titles, ", "))
+	}
+	if !todo.Estimate.IsZero() {
+		fmt.Printf("Estimate: %s\n", todo.Estimate)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(setCmd)
+	addPlanningFlags(setCmd)
+	setCmd.Flags().Bool("unblock", false, "remove all blockers")
+	setCmd.Flags().StringP("estimate", "e", "", "estimate in points (3) or time (2h30m); none clears it")
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prime-run/togo/model"
+	"github.com/prime-run/togo/pkg/togo"
+	"github.com/spf13/cobra"
+)
+
+var snoozeCmd = &cobra.Command{
+	Use:   "snooze [title] <when>",
+	Short: "Hide a todo until a later date",
+	Long: `Hide a todo from the active todos, 'togo agenda' and 'togo next' until a
+later date: "tomorrow", a weekday ("mon"), "next week", a span ("3d", "2w",
+"1mo") or a date ("2006-01-02"), optionally with a time ("09:00"). The todo
+comes back by itself at the start of that day. Use "none" to bring it back
+now, and 'togo list --snoozed' to see the snoozed todos.`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		now := time.Now()
+		until, err := model.ParseWhen(args[len(args)-1], now)
+		handleErrorAndExit(err, "Error:")
+		if !until.IsZero() && !until.After(now) {
+			handleErrorAndExit(fmt.Errorf("%s is not in the future", formatDue(until, now)), "Error:")
+		}
+		client := openClientOrExit()
+		todo := resolveTodoArgOrExit(activeTasks(loadTasksOrExit(client)), args[:len(args)-1], "Select a todo to snooze")
+		updateOrExit(client, func(l *togo.List) error {
+			return l.Snooze(todo.ID, until)
+		})
+		if until.IsZero() {
+			fmt.Printf("Todo \"%s\" is back in the active todos\n", todo.Title)
+			return
+		}
+		fmt.Printf("Todo \"%s\" snoozed until %s\n", todo.Title, formatDue(until, now))
+	},
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completeTaskTitles(func(t togo.Task) bool { return !t.Archived })(cmd, args, toComplete)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(snoozeCmd)
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/prime-run/togo/report"
+	"github.com/prime-run/togo/ui"
+	"github.com/spf13/cobra"
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show statistics about your todos",
+	Long: `Show open and done counts, completions per week, the average and median
+time from creating a todo to completing it, counts per #tag, the oldest open
+todos, and a calendar of completions with your current and longest streak of
+days with at least one completion.
+
+--weeks sets how much history is shown. --format json prints the numbers for
+scripts. Use --source all to include every project below the current
+directory and the global list.`,
+	Annotations: map[string]string{allSourcesAnnotation: "true"},
+	Args:        cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		weeks, _ := cmd.Flags().GetInt("weeks")
+		format, _ := cmd.Flags().GetString("format")
+		if weeks < 1 {
+			handleErrorAndExit(fmt.Errorf("--weeks must be at least 1"), "Error:")
+		}
+		now := time.Now()
+		stats := report.BuildStats(loadTasksOrExit(openStoreOrExit(cmd)), now, weeks)
+		switch format {
+		case "text":
+			width := 80
+			if w, _, err := term.GetSize(os.Stdout.Fd()); err == nil {
+				width = w
+			}
+			fmt.Println(ui.RenderStats(stats, now, width))
+		case "json":
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			handleErrorAndExit(enc.Encode(stats), "Error:")
+		default:
+			handleErrorAndExit(fmt.Errorf("invalid format %q (use text or json)", format), "Error:")
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+	statsCmd.Flags().Int("weeks", ui.StatsWeeks, "weeks of history to show")
+	statsCmd.Flags().String("format", "text", "output format: text or json")
+	_ = statsCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions([]string{"text", "json"}, cobra.ShellCompDirectiveNoFileComp))
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/prime-run/togo/model"
+	"github.com/prime-run/togo/pkg/togo"
+	"github.com/spf13/cobra"
+)
+
+var startCmd = &cobra.Command{
+	Use:   "start <title>",
+	Short: "Start tracking time on a todo",
+	Long: `Start a timer on a pending todo. Only one timer can run at a time, in the
+project, global and registered project lists together; stop it with 'togo
+stop' first. Completing or archiving a todo stops its timer.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client := openClientOrExit()
+		clients := timerClientsOrExit(client)
+		for _, c := range clients {
+			if running, ok := runningTimerOrExit(c); ok {
+				fmt.Printf("Error: timer already running on \"%s\" (%s, %s); run 'togo stop' first\n",
+					running.Title, c.Source(), model.FormatDuration(currentInterval(running)))
+				os.Exit(1)
+			}
+		}
+		todo := resolveTodoArgOrExit(pendingTasks(loadTasksOrExit(client)), args, "Select a todo to work on")
+		updateOrExit(client, func(l *togo.List) error {
+			// Check the other lists again while this one is locked, so a
+			// timer started meanwhile elsewhere is not missed. Two starts in
+			// different lists at the very same moment can still both win.
+			for _, c := range clients[1:] {
+				tasks, err := c.Tasks(cmd.Context())
+				if err != nil {
+					return err
+				}
+				for _, task := range tasks {
+					if task.Running() {
+						return fmt.Errorf("%w on %q (%s)", togo.ErrTimerRunning, task.Title, c.Source())
+					}
+				}
+			}
+			return l.StartTimer(todo.ID)
+		})
+		fmt.Printf("Timer started on \"%s\"\n", todo.Title)
+		if spent := todo.TimeSpent(time.Now()); spent > 0 {
+			fmt.Printf("Tracked so far: %s\n", model.FormatDuration(spent))
+		}
+	},
+	ValidArgsFunction: completeTaskTitles(func(t togo.Task) bool { return !t.Archived && !t.Completed }),
+}
+
+var stopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the running timer",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		for _, c := range timerClientsOrExit(openClientOrExit()) {
+			running, ok := runningTimerOrExit(c)
+			if !ok {
+				continue
+			}
+			var stopped togo.Task
+			updateOrExit(c, func(l *togo.List) error {
+				if err := l.StopTimer(running.ID); err != nil {
+					return err
+				}
+				stopped, _ = l.Task(running.ID)
+				return nil
+			})
+			last := stopped.TimeLog[len(stopped.TimeLog)-1]
+			fmt.Printf("Timer stopped on \"%s\" after %s (total %s)\n",
+				stopped.Title, model.FormatDuration(last.Duration(last.End)), model.FormatDuration(stopped.TimeSpent(time.Now())))
+			return
+		}
+		fmt.Println("No timer running.")
+	},
+}
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the running timer",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		for _, c := range timerClientsOrExit(openClientOrExit()) {
+			if running, ok := runningTimerOrExit(c); ok {
+				fmt.Printf("Working on \"%s\" (%s) for %s, %s in total\n", running.Title, c.Source(),
+					model.FormatDuration(currentInterval(running)), model.FormatDuration(running.TimeSpent(time.Now())))
+				return
+			}
+		}
+		fmt.Println("No timer running.")
+	},
+}
+
+// timerClientsOrExit returns the clients whose timers count as running: the
+// current source first, then the other one of project and global, then the
+// registered projects.
+func timerClientsOrExit(client *togo.Client) []*togo.Client {
+	other := togo.Global
+	if client.Source() == togo.Global {
+		other = togo.Project
+	}
+	otherClient, err := togo.Open(other, togo.WithFileName(TodoFileName), togo.WithHookOutput(os.Stderr))
+	handleErrorAndExit(err, "Error:")
+	clients := []*togo.Client{client, otherClient}
+	projects, err := model.LoadProjects()
+	handleErrorAndExit(err, "Error:")
+	for _, p := range projects {
+		clients = append(clients, togo.OpenFile(filepath.Join(p.Root, TodoFileName), togo.WithFileName(TodoFileName), togo.WithHookOutput(os.Stderr)))
+	}
+	var unique []*togo.Client
+	for _, c := range clients {
+		if !slices.ContainsFunc(unique, func(u *togo.Client) bool { return u.Path() == c.Path() }) {
+			unique = append(unique, c)
+		}
+	}
+	return unique
+}
+
+func runningTimerOrExit(client *togo.Client) (togo.Task, bool) {
+	for _, task := range loadTasksOrExit(client) {
+		if task.Running() {
+			return task, true
+		}
+	}
+	return togo.Task{}, false
+}
+
+func currentInterval(task togo.Task) time.Duration {
+	return task.TimeLog[len(task.TimeLog)-1].Duration(time.Now())
+}
+
+func pendingTasks(tasks []togo.Task) []togo.Task {
+	var pending []togo.Task
+	for _, task := range activeTasks(tasks) {
+		if !task.Completed {
+			pending = append(pending, task)
+		}
+	}
+	return pending
+}
+
+func init() {
+	rootCmd.AddCommand(startCmd, stopCmd, statusCmd)
+}
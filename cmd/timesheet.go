@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prime-run/togo/model"
+	"github.com/prime-run/togo/report"
+	"github.com/spf13/cobra"
+)
+
+var timesheetCmd = &cobra.Command{
+	Use:   "timesheet",
+	Short: "Report the time tracked on todos",
+	Long: `Report the time tracked with 'togo start'/'togo stop' for today, or for the
+current week (Monday to Sunday) with --week, grouped by day and by the #tags in
+todo titles. A todo with several tags counts towards each of them. Use
+--source all to report on every project below the current directory and the
+global list together.`,
+	Annotations: map[string]string{allSourcesAnnotation: "true"},
+	Args:        cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		week, _ := cmd.Flags().GetBool("week")
+		now := time.Now()
+		from := report.StartOfDay(now)
+		to := from.AddDate(0, 0, 1)
+		if week {
+			from = report.StartOfWeek(now)
+			to = from.AddDate(0, 0, 7)
+		}
+
+		sheet := report.BuildTimesheet(loadTasksOrExit(openStoreOrExit(cmd)), from, to, now)
+		if week {
+			fmt.Printf("Timesheet for the week of %s\n\n", from.Format("Mon 2006-01-02"))
+		} else {
+			fmt.Printf("Timesheet for %s\n\n", from.Format("Mon 2006-01-02"))
+		}
+		if len(sheet.Days) == 0 {
+			fmt.Println("No time tracked.")
+			return
+		}
+		for _, day := range sheet.Days {
+			fmt.Printf("%s  %s\n", day.Date.Format("Mon 2006-01-02"), model.FormatDuration(day.Total))
+			printTagTimes(day.Tags)
+		}
+		if week {
+			fmt.Printf("\nTotal  %s\n", model.FormatDuration(sheet.Total))
+			printTagTimes(sheet.Tags)
+		}
+	},
+}
+
+func printTagTimes(tags []report.TagTime) {
+	width := len("untagged")
+	for _, t := range tags {
+		width = max(width, len(t.Tag)+1)
+	}
+	for _, t := range tags {
+		label := "#" + t.Tag
+		if t.Tag == report.Untagged {
+			label = "untagged"
+		}
+		fmt.Printf("    %-*s  %s\n", width, label, model.FormatDuration(t.Duration))
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(timesheetCmd)
+	timesheetCmd.Flags().Bool("week", false, "Report the current week instead of today")
+}
@@ -0,0 +1,142 @@
+// Package config loads the user's togo settings from config.json in the togo
+// config directory. Missing files and settings fall back to defaults.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Duration is a time.Duration written as a string such as "25m" in JSON.
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("durations are strings such as \"25m\": %w", err)
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+type Pomodoro struct {
+	Work           Duration `json:"work"`
+	ShortBreak     Duration `json:"short_break"`
+	LongBreak      Duration `json:"long_break"`
+	LongBreakEvery int      `json:"long_break_every"`
+	// Command is run through the shell at every phase change instead of
+	// ringing the terminal bell.
+	Command string `json:"command,omitempty"`
+}
+
+// TimeFormat is how the TUI shows times until toggled: relative ("3 days
+// ago") or absolute in Layout, a Go time layout that defaults to the one of
+// the user's locale.
+type TimeFormat struct {
+	Absolute bool   `json:"absolute"`
+	Layout   string `json:"layout,omitempty"`
+}
+
+// Scoring weighs what makes a task important for 'togo next' and the order
+// of 'togo agenda'. Each weight multiplies a factor: the priority (0 to 1 for
+// high), the due date (0 two weeks ahead, 1 at the deadline, up to 2 when
+// overdue), the age (0 to 1 at 30 days) and whether the task is blocked (0
+// or 1).
+type Scoring struct {
+	Priority float64 `json:"priority"`
+	Due      float64 `json:"due"`
+	Age      float64 `json:"age"`
+	Blocked  float64 `json:"blocked"`
+}
+
+// Retention is the clean-up 'togo gc' does: archive tasks completed more
+// than ArchiveAfterDays ago, then purge archived tasks older than
+// PurgeAfterDays and all but the KeepArchived most recent ones. Zero turns a
+// rule off. With OnLoad every command applies it when loading the tasks.
+type Retention struct {
+	ArchiveAfterDays int  `json:"archive_completed_after_days,omitempty"`
+	PurgeAfterDays   int  `json:"purge_archived_after_days,omitempty"`
+	KeepArchived     int  `json:"keep_archived,omitempty"`
+	OnLoad           bool `json:"on_load,omitempty"`
+}
+
+type Config struct {
+	Pomodoro   Pomodoro   `json:"pomodoro"`
+	TimeFormat TimeFormat `json:"time_format"`
+	Scoring    Scoring    `json:"scoring"`
+	Retention  Retention  `json:"retention"`
+}
+
+func Default() Config {
+	return Config{
+		Pomodoro: Pomodoro{
+			Work:           Duration(25 * time.Minute),
+			ShortBreak:     Duration(5 * time.Minute),
+			LongBreak:      Duration(15 * time.Minute),
+			LongBreakEvery: 4,
+		},
+		Scoring: Scoring{
+			Priority: 3,
+			Due:      4,
+			Age:      1,
+			Blocked:  -10,
+		},
+	}
+}
+
+func Path() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "togo", "config.json"), nil
+}
+
+// Load reads the config file over the defaults.
+func Load() (Config, error) {
+	cfg := Default()
+	path, err := Path()
+	if err != nil {
+		return cfg, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return Default(), fmt.Errorf("reading %s: %w", path, err)
+	}
+	if err := cfg.validate(); err != nil {
+		return Default(), fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+func (c Config) validate() error {
+	p := c.Pomodoro
+	if p.Work <= 0 || p.ShortBreak <= 0 || p.LongBreak <= 0 {
+		return fmt.Errorf("pomodoro durations must be positive")
+	}
+	if p.LongBreakEvery < 1 {
+		return fmt.Errorf("pomodoro.long_break_every must be at least 1")
+	}
+	r := c.Retention
+	if r.ArchiveAfterDays < 0 || r.PurgeAfterDays < 0 || r.KeepArchived < 0 {
+		return fmt.Errorf("retention settings must not be negative")
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package feed
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prime-run/togo/model"
+)
+
+type Event struct {
+	Type   model.ChangeType `json:"type"`
+	Source string           `json:"source"`
+	Todo   model.Todo       `json:"todo"`
+	Time   time.Time        `json:"time"`
+}
+
+func NewEvents(source string, changes []model.Change) []Event {
+	now := time.Now()
+	events := make([]Event, len(changes))
+	for i, c := range changes {
+		events[i] = Event{Type: c.Type, Source: source, Todo: c.Todo, Time: now}
+	}
+	return events
+}
+
+type Hub struct {
+	mu   sync.Mutex
+	subs map[chan Event]struct{}
+}
+
+func NewHub() *Hub {
+	return &Hub{subs: make(map[chan Event]struct{})}
+}
+
+func (h *Hub) Subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, 64)
+	h.mu.Lock()
+	h.subs[ch] = struct{}{}
+	h.mu.Unlock()
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.subs, ch)
+			h.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// Publish delivers events to every subscriber. Subscribers that fall too far
+// behind miss events rather than blocking the publisher.
+func (h *Hub) Publish(events ...Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subs {
+		for _, ev := range events {
+			select {
+			case ch <- ev:
+			default:
+			}
+		}
+	}
+}
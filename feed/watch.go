@@ -0,0 +1,75 @@
+package feed
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+type Stamp struct {
+	exists  bool
+	size    int64
+	modTime time.Time
+}
+
+func StatFile(path string) Stamp {
+	st, err := os.Stat(path)
+	if err != nil {
+		return Stamp{}
+	}
+	return Stamp{exists: true, size: st.Size(), modTime: st.ModTime()}
+}
+
+// WaitForChange polls path every interval until its size or modification
+// time differs from last, and returns the new stamp. It returns last
+// unchanged if ctx is cancelled first.
+func WaitForChange(ctx context.Context, path string, last Stamp, interval time.Duration) Stamp {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return last
+		case <-ticker.C:
+			if current := StatFile(path); current != last {
+				return current
+			}
+		}
+	}
+}
+
+// WaitForAnyChange is WaitForChange for several files: it polls paths until
+// one of them differs from its stamp in last and returns the stamps of all.
+func WaitForAnyChange(ctx context.Context, paths []string, last []Stamp, interval time.Duration) []Stamp {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return last
+		case <-ticker.C:
+			current := make([]Stamp, len(paths))
+			changed := false
+			for i, path := range paths {
+				current[i] = StatFile(path)
+				changed = changed || i >= len(last) || current[i] != last[i]
+			}
+			if changed {
+				return current
+			}
+		}
+	}
+}
+
+// WatchFile calls onChange every time path changes, until ctx is cancelled.
+func WatchFile(ctx context.Context, path string, interval time.Duration, onChange func()) {
+	last := StatFile(path)
+	for {
+		current := WaitForChange(ctx, path, last, interval)
+		if ctx.Err() != nil {
+			return
+		}
+		last = current
+		onChange()
+	}
+}
@@ -0,0 +1,25 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func EnsureAttribute(repoRoot, line string) (bool, error) {
+	path := filepath.Join(repoRoot, ".gitattributes")
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+	for _, existing := range strings.Split(string(data), "\n") {
+		if strings.Join(strings.Fields(existing), " ") == line {
+			return false, nil
+		}
+	}
+	content := string(data)
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	return true, os.WriteFile(path, []byte(content+line+"\n"), 0644)
+}
@@ -0,0 +1,83 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+func run(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return "", fmt.Errorf("git %s: %s", strings.Join(args, " "), msg)
+	}
+	return strings.TrimRight(stdout.String(), "\n"), nil
+}
+
+func RepoRoot(dir string) (string, error) {
+	return run(dir, "rev-parse", "--show-toplevel")
+}
+
+func GitDir(dir string) (string, error) {
+	out, err := run(dir, "rev-parse", "--git-common-dir")
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(out) {
+		out = filepath.Join(dir, out)
+	}
+	return out, nil
+}
+
+func HooksDir(dir string) (string, error) {
+	out, err := run(dir, "rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(out) {
+		out = filepath.Join(dir, out)
+	}
+	return out, nil
+}
+
+func CurrentBranch(dir string) (string, error) {
+	return run(dir, "rev-parse", "--abbrev-ref", "HEAD")
+}
+
+func HeadCommit(dir string) (hash, message string, err error) {
+	out, err := run(dir, "log", "-1", "--format=%h%n%B")
+	if err != nil {
+		return "", "", err
+	}
+	hash, message, _ = strings.Cut(out, "\n")
+	return hash, message, nil
+}
+
+func BranchExists(dir, name string) bool {
+	_, err := run(dir, "rev-parse", "--verify", "--quiet", "refs/heads/"+name)
+	return err == nil
+}
+
+func Checkout(dir, name string, create bool) error {
+	args := []string{"checkout"}
+	if create {
+		args = append(args, "-b")
+	}
+	_, err := run(dir, append(args, name)...)
+	return err
+}
+
+func SetConfig(dir, key, value string) error {
+	_, err := run(dir, "config", key, value)
+	return err
+}
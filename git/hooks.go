@@ -0,0 +1,54 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const hookMarker = "# installed by togo"
+
+var hookScripts = map[string]string{
+	"commit-msg": `#!/bin/sh
+` + hookMarker + `
+command -v togo >/dev/null 2>&1 || exit 0
+togo git hook commit-msg "$1" || true
+`,
+	"post-commit": `#!/bin/sh
+` + hookMarker + `
+command -v togo >/dev/null 2>&1 || exit 0
+togo git hook post-commit || true
+`,
+}
+
+func HookNames() []string {
+	return []string{"commit-msg", "post-commit"}
+}
+
+func InstallHooks(dir string, force bool) ([]string, error) {
+	hooksDir, err := HooksDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		return nil, err
+	}
+	var installed []string
+	for _, name := range HookNames() {
+		path := filepath.Join(hooksDir, name)
+		if existing, err := os.ReadFile(path); err == nil && !strings.Contains(string(existing), hookMarker) {
+			if !force {
+				return installed, fmt.Errorf("%s already exists and was not installed by togo (use --force to replace it)", path)
+			}
+			if err := os.Rename(path, path+".bak"); err != nil {
+				return installed, err
+			}
+		}
+		if err := os.WriteFile(path, []byte(hookScripts[name]), 0755); err != nil {
+			return installed, err
+		}
+		installed = append(installed, path)
+	}
+	return installed, nil
+}
@@ -0,0 +1,61 @@
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var refPattern = regexp.MustCompile(`(?i)(?:\b(close[sd]?|fix(?:e[sd])?|resolve[sd]?)\s*:?\s+)?\btogo#(\d+)\b`)
+
+var slugPattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+type Ref struct {
+	ID     int
+	Closes bool
+}
+
+func ParseRefs(message string) []Ref {
+	var refs []Ref
+	seen := make(map[int]int)
+	for _, m := range refPattern.FindAllStringSubmatch(message, -1) {
+		id, err := strconv.Atoi(m[2])
+		if err != nil {
+			continue
+		}
+		closes := m[1] != ""
+		if i, ok := seen[id]; ok {
+			refs[i].Closes = refs[i].Closes || closes
+			continue
+		}
+		seen[id] = len(refs)
+		refs = append(refs, Ref{ID: id, Closes: closes})
+	}
+	return refs
+}
+
+func FormatRef(id int) string {
+	return fmt.Sprintf("togo#%d", id)
+}
+
+func BranchName(id int, title string) string {
+	slug := strings.Trim(slugPattern.ReplaceAllString(strings.ToLower(title), "-"), "-")
+	if len(slug) > 40 {
+		slug = strings.TrimRight(slug[:40], "-")
+	}
+	if slug == "" {
+		return fmt.Sprintf("togo-%d", id)
+	}
+	return fmt.Sprintf("togo-%d-%s", id, slug)
+}
+
+func BranchTaskID(branch string) (int, bool) {
+	rest, ok := strings.CutPrefix(branch, "togo-")
+	if !ok {
+		return 0, false
+	}
+	num, _, _ := strings.Cut(rest, "-")
+	id, err := strconv.Atoi(num)
+	return id, err == nil
+}
@@ -0,0 +1,237 @@
+// Package hooks runs user scripts when tasks change.
+//
+// Hooks are executables named after the event they handle (for example
+// "pre-add" or "post-complete.sh") in the user's togo config directory
+// (hooks/) or in the project's .togo-hooks directory. Each hook receives the
+// task as JSON on stdin and TOGO_EVENT in its environment.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prime-run/togo/model"
+)
+
+type Event string
+
+const (
+	PreAdd       Event = "pre-add"
+	PostAdd      Event = "post-add"
+	PreComplete  Event = "pre-complete"
+	PostComplete Event = "post-complete"
+	Archive      Event = "archive"
+	Delete       Event = "delete"
+)
+
+// Before reports whether the event fires before the change is applied. Such
+// hooks can abort the operation with a non-zero exit and can rewrite the task
+// by printing JSON; hooks for the other events only observe.
+func (e Event) Before() bool {
+	return e != PostAdd && e != PostComplete
+}
+
+// Timeout bounds how long a single hook may run.
+const Timeout = 30 * time.Second
+
+const projectDir = ".togo-hooks"
+
+type Error struct {
+	Event  Event
+	Script string
+	Err    error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s hook %s: %v", e.Event, filepath.Base(e.Script), e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Runner executes the hooks found in Dirs. Hook stderr goes to Stderr; when it
+// is nil, the last line a failing hook wrote is folded into its error instead.
+type Runner struct {
+	Dirs   []string
+	Stderr io.Writer
+}
+
+// New returns a runner for the global hooks directory and the hooks of the
+// current project, in that order.
+func New(stderr io.Writer) *Runner {
+	return &Runner{Dirs: DefaultDirs(), Stderr: stderr}
+}
+
+func DefaultDirs() []string {
+	var dirs []string
+	if dir, err := os.UserConfigDir(); err == nil {
+		dirs = append(dirs, filepath.Join(dir, "togo", "hooks"))
+	}
+	if root, ok := model.FindProjectRoot(); ok {
+		dirs = append(dirs, filepath.Join(root, projectDir))
+	}
+	return dirs
+}
+
+// Scripts lists the executables that handle event, sorted by name within each
+// directory.
+func (r *Runner) Scripts(event Event) []string {
+	var scripts []string
+	for _, dir := range r.Dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name := entry.Name()
+			if name != string(event) && !strings.HasPrefix(name, string(event)+".") {
+				continue
+			}
+			if strings.HasSuffix(name, ".sample") || strings.HasSuffix(name, "~") {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil || !info.Mode().IsRegular() {
+				continue
+			}
+			if runtime.GOOS != "windows" && info.Mode().Perm()&0o111 == 0 {
+				continue
+			}
+			scripts = append(scripts, filepath.Join(dir, name))
+		}
+	}
+	return scripts
+}
+
+// Run invokes every hook for event with todo. For events that fire before a
+// change, the first failing hook aborts with an *Error and each hook sees the
+// task as rewritten by the previous one. For the others, all hooks run and
+// their failures are joined.
+func (r *Runner) Run(ctx context.Context, event Event, todo model.Todo) (model.Todo, error) {
+	if r == nil {
+		return todo, nil
+	}
+	var errs []error
+	for _, script := range r.Scripts(event) {
+		out, err := r.exec(ctx, event, script, todo)
+		if err != nil {
+			err = &Error{Event: event, Script: script, Err: err}
+			if event.Before() {
+				return todo, err
+			}
+			errs = append(errs, err)
+			continue
+		}
+		if !event.Before() {
+			continue
+		}
+		if todo, err = apply(todo, out); err != nil {
+			return todo, &Error{Event: event, Script: script, Err: err}
+		}
+	}
+	return todo, errors.Join(errs...)
+}
+
+// Add runs the pre-add hooks for a new task built from draft, then adds the
+// task to tl as the hooks left it. The ID, UID and creation time of draft are
+// assigned here.
+func (r *Runner) Add(ctx context.Context, tl *model.TodoList, draft model.Todo) (*model.Todo, error) {
+	draft.ID, draft.UID, draft.CreatedAt = tl.NextID, "", time.Now()
+	draft.List = model.NormalizeListName(draft.List)
+	draft, err := r.Run(ctx, PreAdd, draft)
+	if err != nil {
+		return nil, err
+	}
+	todo := tl.Add(draft.Title)
+	draft.ID = todo.ID
+	tl.Replace(draft)
+	return tl.GetTodoByID(todo.ID), nil
+}
+
+// Mutate runs the hooks for a before-event against the task with the given
+// id, stores whatever they changed and then applies the operation itself.
+func (r *Runner) Mutate(ctx context.Context, tl *model.TodoList, event Event, id int, op func(int) bool) error {
+	todo := tl.GetTodoByID(id)
+	if todo == nil {
+		return fmt.Errorf("no todo with id %d", id)
+	}
+	updated, err := r.Run(ctx, event, *todo)
+	if err != nil {
+		return err
+	}
+	tl.Replace(updated)
+	op(id)
+	return nil
+}
+
+func (r *Runner) exec(ctx context.Context, event Event, script string, todo model.Todo) ([]byte, error) {
+	input, err := json.Marshal(todo)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, Timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, script)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Env = append(os.Environ(),
+		"TOGO_EVENT="+string(event),
+		"TOGO_TASK_ID="+strconv.Itoa(todo.ID),
+	)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = r.Stderr
+	if r.Stderr == nil {
+		cmd.Stderr = &stderr
+	}
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("timed out after %s", Timeout)
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			lines := strings.Split(msg, "\n")
+			return nil, fmt.Errorf("%w: %s", err, lines[len(lines)-1])
+		}
+		return nil, err
+	}
+	return stdout.Bytes(), nil
+}
+
+// apply decodes a hook's stdout over todo. Empty output leaves the task as is;
+// the ID and UID cannot be changed, and a new list name must be one the CLI
+// accepts.
+func apply(todo model.Todo, out []byte) (model.Todo, error) {
+	if len(bytes.TrimSpace(out)) == 0 {
+		return todo, nil
+	}
+	updated := todo
+	if err := json.Unmarshal(out, &updated); err != nil {
+		return todo, fmt.Errorf("invalid JSON output: %w", err)
+	}
+	updated.ID = todo.ID
+	updated.UID = todo.UID
+	if strings.TrimSpace(updated.Title) == "" {
+		return todo, errors.New("hook output has an empty title")
+	}
+	if updated.List = model.NormalizeListName(updated.List); updated.List != todo.List {
+		if err := model.CheckListName(updated.List); err != nil {
+			return todo, fmt.Errorf("hook output has an invalid list: %w", err)
+		}
+	}
+	return updated, nil
+}
@@ -0,0 +1,195 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"runtime/debug"
+	"strings"
+	"sync"
+
+	"github.com/prime-run/togo/pkg/togo"
+)
+
+const protocolVersion = "2024-11-05"
+
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+)
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string {
+	return e.Message
+}
+
+type Server struct {
+	defaultSource string
+	opts          []togo.Option
+	mu            sync.Mutex
+	clients       map[string]*togo.Client
+}
+
+// NewServer returns a server for the todo lists of both sources, opened with
+// opts the same way the togo command opens them.
+func NewServer(defaultSource string, opts ...togo.Option) *Server {
+	return &Server{defaultSource: defaultSource, opts: opts, clients: make(map[string]*togo.Client)}
+}
+
+// Serve reads newline-delimited JSON-RPC messages from r and writes the
+// responses to w until r is exhausted or ctx is cancelled.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 16<<20)
+	enc := json.NewEncoder(w)
+	for sc.Scan() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		if resp := s.handle(ctx, []byte(line)); resp != nil {
+			if err := enc.Encode(resp); err != nil {
+				return err
+			}
+		}
+	}
+	return sc.Err()
+}
+
+func (s *Server) handle(ctx context.Context, data []byte) *response {
+	var req request
+	if err := json.Unmarshal(data, &req); err != nil {
+		return &response{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpcError{Code: codeParseError, Message: err.Error()}}
+	}
+	notification := len(req.ID) == 0
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		if notification {
+			return nil
+		}
+		return &response{JSONRPC: "2.0", ID: req.ID, Error: &rpcError{Code: codeInvalidRequest, Message: "invalid JSON-RPC 2.0 request"}}
+	}
+	result, err := s.dispatch(ctx, req)
+	if notification {
+		return nil
+	}
+	resp := &response{JSONRPC: "2.0", ID: req.ID, Result: result}
+	if err != nil {
+		var rerr *rpcError
+		if !errors.As(err, &rerr) {
+			rerr = &rpcError{Code: codeInternalError, Message: err.Error()}
+		}
+		resp.Result = nil
+		resp.Error = rerr
+	}
+	return resp
+}
+
+func (s *Server) dispatch(ctx context.Context, req request) (any, error) {
+	switch req.Method {
+	case "initialize":
+		return map[string]any{
+			"protocolVersion": protocolVersion,
+			"capabilities": map[string]any{
+				"tools":     map[string]any{},
+				"resources": map[string]any{},
+			},
+			"serverInfo": map[string]any{"name": "togo", "version": buildVersion()},
+		}, nil
+	case "notifications/initialized", "notifications/cancelled":
+		// Sent with an id by mistake, these still need a result to answer.
+		return map[string]any{}, nil
+	case "ping":
+		return map[string]any{}, nil
+	case "tools/list":
+		return map[string]any{"tools": toolDefinitions()}, nil
+	case "tools/call":
+		var params struct {
+			Name      string          `json:"name"`
+			Arguments json.RawMessage `json:"arguments"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &rpcError{Code: codeInvalidParams, Message: err.Error()}
+		}
+		return s.callTool(ctx, params.Name, params.Arguments)
+	case "resources/list":
+		return map[string]any{"resources": resourceList()}, nil
+	case "resources/read":
+		var params struct {
+			URI string `json:"uri"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &rpcError{Code: codeInvalidParams, Message: err.Error()}
+		}
+		return s.readResource(ctx, params.URI)
+	default:
+		return nil, &rpcError{Code: codeMethodNotFound, Message: fmt.Sprintf("method %q not found", req.Method)}
+	}
+}
+
+func buildVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		return info.Main.Version
+	}
+	return "(devel)"
+}
+
+func (s *Server) resolveSource(source string) (string, error) {
+	switch source = strings.ToLower(strings.TrimSpace(source)); source {
+	case "":
+		return s.defaultSource, nil
+	case togo.Project, togo.Global:
+		return source, nil
+	default:
+		return "", fmt.Errorf("invalid source %q (must be 'project' or 'global')", source)
+	}
+}
+
+// client returns the client of a source, opening it on first use.
+func (s *Server) client(source string) (*togo.Client, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if c, ok := s.clients[source]; ok {
+		return c, nil
+	}
+	c, err := togo.Open(source, s.opts...)
+	if err != nil {
+		return nil, err
+	}
+	s.clients[source] = c
+	return c, nil
+}
+
+func (s *Server) tasks(ctx context.Context, source string) ([]togo.Task, error) {
+	c, err := s.client(source)
+	if err != nil {
+		return nil, err
+	}
+	return c.Tasks(ctx)
+}
@@ -0,0 +1,156 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/prime-run/togo/pkg/togo"
+)
+
+type testClient struct {
+	t      *testing.T
+	enc    *json.Encoder
+	dec    *json.Decoder
+	nextID int
+}
+
+// call sends a request and decodes the result of its response into result.
+func (c *testClient) call(method string, params, result any) {
+	c.t.Helper()
+	c.nextID++
+	req := map[string]any{"jsonrpc": "2.0", "id": c.nextID, "method": method}
+	if params != nil {
+		req["params"] = params
+	}
+	if err := c.enc.Encode(req); err != nil {
+		c.t.Fatalf("%s: sending request: %v", method, err)
+	}
+	var resp struct {
+		ID     int             `json:"id"`
+		Result json.RawMessage `json:"result"`
+		Error  *rpcError       `json:"error"`
+	}
+	if err := c.dec.Decode(&resp); err != nil {
+		c.t.Fatalf("%s: reading response: %v", method, err)
+	}
+	if resp.ID != c.nextID {
+		c.t.Fatalf("%s: response id = %d, want %d", method, resp.ID, c.nextID)
+	}
+	if resp.Error != nil {
+		c.t.Fatalf("%s: %v", method, resp.Error)
+	}
+	if err := json.Unmarshal(resp.Result, result); err != nil {
+		c.t.Fatalf("%s: decoding result %s: %v", method, resp.Result, err)
+	}
+}
+
+// callTool calls a tool that returns a task and decodes it.
+func (c *testClient) callTool(name string, args map[string]any) togo.Task {
+	c.t.Helper()
+	var result struct {
+		Content []struct {
+			Text string `json:"text"`
+		} `json:"content"`
+		IsError bool `json:"isError"`
+	}
+	c.call("tools/call", map[string]any{"name": name, "arguments": args}, &result)
+	if result.IsError || len(result.Content) == 0 {
+		c.t.Fatalf("%s: tool failed: %+v", name, result)
+	}
+	var task togo.Task
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &task); err != nil {
+		c.t.Fatalf("%s: decoding task: %v", name, err)
+	}
+	return task
+}
+
+func TestServe(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", filepath.Join(dir, "data"))
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(dir, "config"))
+	if err := os.WriteFile(filepath.Join(dir, ".togo"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(dir)
+
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		err := NewServer(togo.Project, togo.WithoutHooks()).Serve(context.Background(), inR, outW)
+		outW.Close()
+		done <- err
+	}()
+	c := &testClient{t: t, enc: json.NewEncoder(inW), dec: json.NewDecoder(outR)}
+
+	var initialized struct {
+		ProtocolVersion string `json:"protocolVersion"`
+		ServerInfo      struct {
+			Name string `json:"name"`
+		} `json:"serverInfo"`
+	}
+	c.call("initialize", map[string]any{"protocolVersion": protocolVersion}, &initialized)
+	if initialized.ProtocolVersion != protocolVersion || initialized.ServerInfo.Name != "togo" {
+		t.Errorf("initialize = %+v", initialized)
+	}
+
+	var empty map[string]any
+	c.call("notifications/initialized", nil, &empty)
+	if len(empty) != 0 {
+		t.Errorf("notifications/initialized with an id = %v, want an empty result", empty)
+	}
+
+	var listed struct {
+		Tools []tool `json:"tools"`
+	}
+	c.call("tools/list", nil, &listed)
+	var names []string
+	for _, tool := range listed.Tools {
+		names = append(names, tool.Name)
+	}
+	for _, want := range []string{"list_todos", "search_todos", "add_todo", "toggle_todo", "archive_todo"} {
+		if !slices.Contains(names, want) {
+			t.Errorf("tools/list is missing %q: %v", want, names)
+		}
+	}
+
+	added := c.callTool("add_todo", map[string]any{"title": "Write tests"})
+	if added.Title != "Write tests" || added.Completed {
+		t.Errorf("add_todo = %+v", added)
+	}
+	toggled := c.callTool("toggle_todo", map[string]any{"id": added.ID})
+	if toggled.ID != added.ID || !toggled.Completed {
+		t.Errorf("toggle_todo = %+v", toggled)
+	}
+
+	var read struct {
+		Contents []struct {
+			URI  string `json:"uri"`
+			Text string `json:"text"`
+		} `json:"contents"`
+	}
+	c.call("resources/read", map[string]any{"uri": "togo://project/todos"}, &read)
+	if len(read.Contents) != 1 {
+		t.Fatalf("resources/read returned %d contents", len(read.Contents))
+	}
+	var tasks []togo.Task
+	if err := json.Unmarshal([]byte(read.Contents[0].Text), &tasks); err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 1 || tasks[0].UID != added.UID || !tasks[0].Completed {
+		t.Errorf("resources/read = %+v", tasks)
+	}
+	if _, err := os.Stat(filepath.Join(dir, togo.DefaultFileName)); err != nil {
+		t.Errorf("project todo file not written: %v", err)
+	}
+
+	inW.Close()
+	if err := <-done; err != nil {
+		t.Errorf("Serve: %v", err)
+	}
+}
@@ -0,0 +1,251 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/prime-run/togo/hooks"
+	"github.com/prime-run/togo/pkg/togo"
+)
+
+type tool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"inputSchema"`
+}
+
+var sourceProperty = map[string]any{
+	"type":        "string",
+	"enum":        []string{"project", "global"},
+	"description": "Todo source; defaults to the project list resolved from the closest .togo file.",
+}
+
+func objectSchema(properties map[string]any, required ...string) map[string]any {
+	properties["source"] = sourceProperty
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func toolDefinitions() []tool {
+	idProperty := map[string]any{"type": "integer", "description": "Todo ID"}
+	return []tool{
+		{
+			Name:        "list_todos",
+			Description: "List todos. By default only active (non-archived) todos are returned.",
+			InputSchema: objectSchema(map[string]any{
+				"status":   map[string]any{"type": "string", "enum": []string{"all", "pending", "completed"}},
+				"archived": map[string]any{"type": "string", "enum": []string{"false", "true", "all"}},
+			}),
+		},
+		{
+			Name:        "search_todos",
+			Description: "Find todos whose title contains the query (case-insensitive), including archived ones.",
+			InputSchema: objectSchema(map[string]any{
+				"query": map[string]any{"type": "string"},
+			}, "query"),
+		},
+		{
+			Name:        "add_todo",
+			Description: "Add a new pending todo.",
+			InputSchema: objectSchema(map[string]any{
+				"title": map[string]any{"type": "string"},
+			}, "title"),
+		},
+		{
+			Name:        "toggle_todo",
+			Description: "Toggle a todo between pending and completed.",
+			InputSchema: objectSchema(map[string]any{"id": idProperty}, "id"),
+		},
+		{
+			Name:        "archive_todo",
+			Description: "Archive a todo, or restore it with unarchive: true.",
+			InputSchema: objectSchema(map[string]any{
+				"id":        idProperty,
+				"unarchive": map[string]any{"type": "boolean"},
+			}, "id"),
+		},
+	}
+}
+
+type toolArgs struct {
+	Source    string `json:"source"`
+	Status    string `json:"status"`
+	Archived  string `json:"archived"`
+	Query     string `json:"query"`
+	Title     string `json:"title"`
+	ID        int    `json:"id"`
+	Unarchive bool   `json:"unarchive"`
+}
+
+func (s *Server) callTool(ctx context.Context, name string, raw json.RawMessage) (any, error) {
+	var args toolArgs
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &args); err != nil {
+			return nil, &rpcError{Code: codeInvalidParams, Message: err.Error()}
+		}
+	}
+	source, err := s.resolveSource(args.Source)
+	if err != nil {
+		return toolError(err), nil
+	}
+
+	switch name {
+	case "list_todos":
+		tasks, err := s.tasks(ctx, source)
+		if err != nil {
+			return toolError(err), nil
+		}
+		return toolJSON(filterTodos(tasks, func(t togo.Task) bool {
+			return matchStatus(t, args.Status) && matchArchived(t, args.Archived)
+		}))
+	case "search_todos":
+		query := strings.ToLower(strings.TrimSpace(args.Query))
+		if query == "" {
+			return toolError(fmt.Errorf("query is required")), nil
+		}
+		tasks, err := s.tasks(ctx, source)
+		if err != nil {
+			return toolError(err), nil
+		}
+		return toolJSON(filterTodos(tasks, func(t togo.Task) bool {
+			return strings.Contains(strings.ToLower(t.Title), query)
+		}))
+	case "add_todo":
+		title := strings.TrimSpace(args.Title)
+		if title == "" {
+			return toolError(fmt.Errorf("title is required")), nil
+		}
+		c, err := s.client(source)
+		if err != nil {
+			return toolError(err), nil
+		}
+		return changeResult(c.Add(ctx, title))
+	case "toggle_todo", "archive_todo":
+		c, err := s.client(source)
+		if err != nil {
+			return toolError(err), nil
+		}
+		switch {
+		case name == "toggle_todo":
+			return changeResult(c.Toggle(ctx, args.ID))
+		case args.Unarchive:
+			return changeResult(c.Unarchive(ctx, args.ID))
+		default:
+			return changeResult(c.Archive(ctx, args.ID))
+		}
+	default:
+		return nil, &rpcError{Code: codeInvalidParams, Message: fmt.Sprintf("unknown tool %q", name)}
+	}
+}
+
+// changeResult reports a change the way the togo command does: a change a
+// hook rejected is an error, while a failing post-add or post-complete hook
+// only adds a warning to the saved task.
+func changeResult(task togo.Task, err error) (any, error) {
+	var postErr *togo.PostHookError
+	var hookErr *hooks.Error
+	switch {
+	case err == nil:
+		return toolJSON(task)
+	case errors.As(err, &postErr):
+		return toolJSON(task, "Warning: "+postErr.Err.Error())
+	case errors.As(err, &hookErr):
+		return toolError(fmt.Errorf("aborted by hook: %w", err)), nil
+	default:
+		return toolError(err), nil
+	}
+}
+
+func filterTodos(todos []togo.Task, keep func(togo.Task) bool) []togo.Task {
+	filtered := []togo.Task{}
+	for _, todo := range todos {
+		if keep(todo) {
+			filtered = append(filtered, todo)
+		}
+	}
+	return filtered
+}
+
+func matchStatus(todo togo.Task, status string) bool {
+	switch status {
+	case "pending":
+		return !todo.Completed
+	case "completed":
+		return todo.Completed
+	default:
+		return true
+	}
+}
+
+func matchArchived(todo togo.Task, archived string) bool {
+	switch archived {
+	case "true":
+		return todo.Archived
+	case "all":
+		return true
+	default:
+		return !todo.Archived
+	}
+}
+
+func toolJSON(v any, notes ...string) (any, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	content := []map[string]any{{"type": "text", "text": string(data)}}
+	for _, note := range notes {
+		content = append(content, map[string]any{"type": "text", "text": note})
+	}
+	return map[string]any{"content": content}, nil
+}
+
+func toolError(err error) any {
+	return map[string]any{
+		"content": []map[string]any{{"type": "text", "text": err.Error()}},
+		"isError": true,
+	}
+}
+
+func resourceURI(source string) string {
+	return "togo://" + source + "/todos"
+}
+
+func resourceList() []map[string]any {
+	var resources []map[string]any
+	for _, source := range []string{"project", "global"} {
+		resources = append(resources, map[string]any{
+			"uri":         resourceURI(source),
+			"name":        source + " todos",
+			"description": "All todos in the " + source + " list",
+			"mimeType":    "application/json",
+		})
+	}
+	return resources
+}
+
+func (s *Server) readResource(ctx context.Context, uri string) (any, error) {
+	for _, source := range []string{togo.Project, togo.Global} {
+		if uri != resourceURI(source) {
+			continue
+		}
+		tasks, err := s.tasks(ctx, source)
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.MarshalIndent(tasks, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{
+			"contents": []map[string]any{{"uri": uri, "mimeType": "application/json", "text": string(data)}},
+		}, nil
+	}
+	return nil, &rpcError{Code: codeInvalidParams, Message: fmt.Sprintf("unknown resource %q", uri)}
+}
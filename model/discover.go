@@ -0,0 +1,79 @@
+package model
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// skippedDirs are never searched for nested projects.
+var skippedDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+	"target":       true,
+	"dist":         true,
+	"build":        true,
+	"__pycache__":  true,
+	"venv":         true,
+}
+
+// maxLooseDepth limits how far below root FindProjectFiles looks when root
+// is not inside a project, so running it from a home directory stays quick.
+const maxLooseDepth = 4
+
+// FindProjectFiles returns the .togo files of the project root is nested in,
+// if root is not a project itself, followed by those of every project nested
+// below root, in path order. Hidden directories and the usual dependency and
+// build directories are not searched; outside a project only the first
+// maxLooseDepth levels below root are.
+func FindProjectFiles(root string) ([]string, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	inProject := isTogoFile(filepath.Join(root, ".togo"))
+	if !inProject {
+		for dir := filepath.Dir(root); ; dir = filepath.Dir(dir) {
+			if candidate := filepath.Join(dir, ".togo"); isTogoFile(candidate) {
+				files = append(files, candidate)
+				inProject = true
+				break
+			}
+			if filepath.Dir(dir) == dir {
+				break
+			}
+		}
+	}
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if d != nil && d.IsDir() && path != root {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() {
+			if path == root {
+				return nil
+			}
+			if strings.HasPrefix(d.Name(), ".") || skippedDirs[d.Name()] {
+				return filepath.SkipDir
+			}
+			if rel, _ := filepath.Rel(root, path); !inProject && strings.Count(rel, string(filepath.Separator)) >= maxLooseDepth {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Name() == ".togo" && d.Type().IsRegular() {
+			files = append(files, path)
+		}
+		return nil
+	})
+	return files, err
+}
+
+func isTogoFile(path string) bool {
+	st, err := os.Stat(path)
+	return err == nil && !st.IsDir()
+}
@@ -0,0 +1,89 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Estimate is the expected size of a task, either in story points or as a
+// duration. The zero Estimate means the task is not estimated.
+type Estimate struct {
+	Points  float64 `json:"points,omitempty"`
+	Minutes int     `json:"minutes,omitempty"`
+}
+
+// ParseEstimate reads an estimate such as "3", "3pt", "1.5 points", "90m" or
+// "2h30m". "", "none" and "-" give the zero Estimate.
+func ParseEstimate(s string) (Estimate, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	switch s {
+	case "", "none", "-":
+		return Estimate{}, nil
+	}
+	number, points := s, false
+	for _, suffix := range []string{"points", "point", "pts", "pt", "p"} {
+		if rest, ok := strings.CutSuffix(s, suffix); ok {
+			number, points = strings.TrimSpace(rest), true
+			break
+		}
+	}
+	if n, err := strconv.ParseFloat(number, 64); err == nil {
+		if n <= 0 || math.IsInf(n, 0) || math.IsNaN(n) {
+			return Estimate{}, fmt.Errorf("invalid estimate %q: points must be positive", s)
+		}
+		return Estimate{Points: n}, nil
+	} else if points {
+		return Estimate{}, fmt.Errorf("invalid estimate %q", s)
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return Estimate{}, fmt.Errorf("invalid estimate %q: use points (3, 2.5pt) or a duration (90m, 2h30m)", s)
+	}
+	if d < time.Minute {
+		return Estimate{}, fmt.Errorf("invalid estimate %q: durations must be at least a minute", s)
+	}
+	return Estimate{Minutes: int(d.Round(time.Minute) / time.Minute)}, nil
+}
+
+func (e Estimate) IsZero() bool {
+	return e.Points == 0 && e.Minutes == 0
+}
+
+func (e Estimate) Duration() time.Duration {
+	return time.Duration(e.Minutes) * time.Minute
+}
+
+func (e Estimate) String() string {
+	switch {
+	case e.Points != 0:
+		return FormatPoints(e.Points)
+	case e.Minutes != 0:
+		return FormatDuration(e.Duration())
+	}
+	return "none"
+}
+
+// FormatPoints formats story points as e.g. "1 pt" or "2.5 pts".
+func FormatPoints(points float64) string {
+	s := strconv.FormatFloat(points, 'f', -1, 64)
+	if points == 1 {
+		return s + " pt"
+	}
+	return s + " pts"
+}
+
+// SetEstimate sets or, with the zero Estimate, clears a todo's estimate.
+func (tl *TodoList) SetEstimate(id int, estimate Estimate) bool {
+	idx := tl.findIndexByID(id)
+	if idx == -1 {
+		return false
+	}
+	if tl.Todos[idx].Estimate != estimate {
+		tl.Todos[idx].Estimate = estimate
+		tl.emitByIndex(ChangeEdited, idx)
+	}
+	return true
+}
@@ -0,0 +1,65 @@
+package model
+
+import "time"
+
+type ChangeType string
+
+const (
+	ChangeAdded      ChangeType = "added"
+	ChangeEdited     ChangeType = "edited"
+	ChangeToggled    ChangeType = "toggled"
+	ChangeArchived   ChangeType = "archived"
+	ChangeUnarchived ChangeType = "unarchived"
+	ChangeDeleted    ChangeType = "deleted"
+)
+
+type Change struct {
+	Type ChangeType `json:"type"`
+	Todo Todo       `json:"todo"`
+}
+
+func (tl *TodoList) OnChange(fn func(Change)) {
+	tl.listeners = append(tl.listeners, fn)
+}
+
+func (tl *TodoList) emit(changeType ChangeType, todo Todo) {
+	for _, fn := range tl.listeners {
+		fn(Change{Type: changeType, Todo: todo})
+	}
+}
+
+// emitByIndex is called by every mutator once it changed the todo at idx: it
+// stamps the todo's modification time and notifies the listeners.
+func (tl *TodoList) emitByIndex(changeType ChangeType, idx int) {
+	tl.Todos[idx].UpdatedAt = time.Now()
+	tl.emit(changeType, tl.Todos[idx])
+}
+
+// DiffTodoLists describes how newer differs from older as the changes a
+// sequence of mutations would have emitted. Tasks are matched by UID.
+func DiffTodoLists(older, newer *TodoList) []Change {
+	oldByUID := indexTodos(older)
+	newByUID := indexTodos(newer)
+	var changes []Change
+	for _, todo := range newer.Todos {
+		prev, existed := oldByUID[todo.UID]
+		switch {
+		case !existed:
+			changes = append(changes, Change{Type: ChangeAdded, Todo: todo})
+		case prev.Completed != todo.Completed:
+			changes = append(changes, Change{Type: ChangeToggled, Todo: todo})
+		case !prev.Archived && todo.Archived:
+			changes = append(changes, Change{Type: ChangeArchived, Todo: todo})
+		case prev.Archived && !todo.Archived:
+			changes = append(changes, Change{Type: ChangeUnarchived, Todo: todo})
+		case !sameTodo(prev, todo):
+			changes = append(changes, Change{Type: ChangeEdited, Todo: todo})
+		}
+	}
+	for _, todo := range older.Todos {
+		if _, exists := newByUID[todo.UID]; !exists {
+			changes = append(changes, Change{Type: ChangeDeleted, Todo: todo})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+func newUID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return legacyUID(0, time.Now())
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return formatUID(b)
+}
+
+// legacyUID derives a stable UID for tasks written before UIDs existed, so
+// that every copy of an old file (e.g. the three sides of a merge) agrees
+// on the identity of its tasks.
+func legacyUID(id int, createdAt time.Time) string {
+	sum := sha1.Sum([]byte("togo:" + strconv.Itoa(id) + ":" + createdAt.UTC().Format(time.RFC3339Nano)))
+	var b [16]byte
+	copy(b[:], sum[:16])
+	b[6] = (b[6] & 0x0f) | 0x50
+	b[8] = (b[8] & 0x3f) | 0x80
+	return formatUID(b)
+}
+
+func formatUID(b [16]byte) string {
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// Encode writes the list as indented JSON with one task per line, which keeps
+// diffs and merges of the file readable.
+func (tl *TodoList) Encode() ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "{\n  \"version\": %d,\n  \"next_id\": %d,\n  \"todos\": [", tl.Version, tl.NextID)
+	for i, todo := range tl.Todos {
+		line, err := json.Marshal(todo)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString("\n    ")
+		buf.Write(line)
+	}
+	if len(tl.Todos) > 0 {
+		buf.WriteString("\n  ")
+	}
+	buf.WriteString("]\n}\n")
+	return buf.Bytes(), nil
+}
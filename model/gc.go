@@ -0,0 +1,116 @@
+package model
+
+import (
+	"bufio"
+	"cmp"
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Retention says which completed and archived todos are cleaned up. A zero
+// field turns its rule off.
+type Retention struct {
+	// ArchiveAfter archives todos completed longer ago than this.
+	ArchiveAfter time.Duration
+	// PurgeAfter removes archived todos archived longer ago than this.
+	PurgeAfter time.Duration
+	// KeepArchived removes all but this many archived todos, keeping the
+	// most recently archived ones.
+	KeepArchived int
+}
+
+func (r Retention) IsZero() bool {
+	return r == Retention{}
+}
+
+// Collection lists the todos a retention policy archived and purged.
+type Collection struct {
+	Archived []Todo
+	Purged   []Todo
+}
+
+func (c Collection) IsEmpty() bool {
+	return len(c.Archived) == 0 && len(c.Purged) == 0
+}
+
+// Collect applies r at now: completed todos past r.ArchiveAfter are
+// archived, then archived todos past r.PurgeAfter or beyond the
+// r.KeepArchived most recent are deleted. Todos whose completion or archive
+// time is unknown count from their last modification.
+func (tl *TodoList) Collect(r Retention, now time.Time) Collection {
+	var c Collection
+	if r.ArchiveAfter > 0 {
+		for _, todo := range tl.Todos {
+			if todo.Completed && !todo.Archived && now.Sub(since(todo.CompletedAt, todo)) > r.ArchiveAfter {
+				tl.Archive(todo.ID)
+				c.Archived = append(c.Archived, *tl.GetTodoByID(todo.ID))
+			}
+		}
+	}
+	archived := tl.GetArchivedTodos()
+	slices.SortStableFunc(archived, func(a, b Todo) int {
+		return cmp.Compare(since(b.ArchivedAt, b).UnixNano(), since(a.ArchivedAt, a).UnixNano())
+	})
+	for i, todo := range archived {
+		expired := r.PurgeAfter > 0 && now.Sub(since(todo.ArchivedAt, todo)) > r.PurgeAfter
+		if expired || (r.KeepArchived > 0 && i >= r.KeepArchived) {
+			c.Purged = append(c.Purged, todo)
+		}
+	}
+	for _, todo := range c.Purged {
+		tl.Delete(todo.ID)
+	}
+	return c
+}
+
+func since(t time.Time, todo Todo) time.Time {
+	if !t.IsZero() {
+		return t
+	}
+	if !todo.UpdatedAt.IsZero() {
+		return todo.UpdatedAt
+	}
+	return todo.CreatedAt
+}
+
+// PurgeFilePath returns the file todos purged from the todo file at
+// filePath are kept in: todos.json has todos.purged.jsonl.gz.
+func PurgeFilePath(filePath string) string {
+	return strings.TrimSuffix(filePath, filepath.Ext(filePath)) + ".purged.jsonl.gz"
+}
+
+// AppendPurged adds todos to the gzip-compressed purge file at path, one
+// JSON object per line. Each call appends a gzip member, which gzip readers
+// read as one stream.
+func AppendPurged(path string, todos []Todo) error {
+	if len(todos) == 0 {
+		return nil
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(f)
+	w := bufio.NewWriter(zw)
+	enc := json.NewEncoder(w)
+	for _, todo := range todos {
+		if err := enc.Encode(todo); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
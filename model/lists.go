@@ -0,0 +1,59 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// DefaultList is the name shown for tasks that are not in a named list.
+const DefaultList = "default"
+
+// NormalizeListName trims a list name and maps the default list to "", which
+// is how it is stored.
+func NormalizeListName(name string) string {
+	name = strings.TrimSpace(name)
+	if strings.EqualFold(name, DefaultList) {
+		return ""
+	}
+	return name
+}
+
+// CheckListName rejects list names that would be mistaken for a source or a
+// path where either is accepted, such as in "togo move --to".
+func CheckListName(name string) error {
+	n := strings.ToLower(strings.TrimSpace(name))
+	switch {
+	case n == "project" || n == "global" || n == "all":
+		return fmt.Errorf("%q is the name of a source and cannot be used for a list", name)
+	case strings.ContainsAny(n, `/\`) || strings.HasPrefix(n, ".") || strings.HasPrefix(n, "~") || strings.HasSuffix(n, ".json"):
+		return fmt.Errorf("list name %q looks like a path", name)
+	}
+	return nil
+}
+
+// ListName returns the name of the list the todo belongs to.
+func (t Todo) ListName() string {
+	if t.List == "" {
+		return DefaultList
+	}
+	return t.List
+}
+
+// InList reports whether the todo belongs to the named list.
+func (t Todo) InList(name string) bool {
+	return t.List == NormalizeListName(name)
+}
+
+// ListNames returns the default list followed by the other lists used by
+// todos, sorted by name.
+func ListNames(todos []Todo) []string {
+	var names []string
+	for _, todo := range todos {
+		if todo.List != "" && !slices.Contains(names, todo.List) {
+			names = append(names, todo.List)
+		}
+	}
+	slices.Sort(names)
+	return append([]string{DefaultList}, names...)
+}
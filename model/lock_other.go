@@ -0,0 +1,7 @@
+//go:build !unix
+
+package model
+
+func lockFile(filePath string) (func(), error) {
+	return func() {}, nil
+}
@@ -0,0 +1,26 @@
+//go:build unix
+
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// lockFile takes an exclusive advisory lock on the directory holding
+// filePath, which avoids leaving lock files next to project todo files.
+func lockFile(filePath string) (func(), error) {
+	f, err := os.Open(filepath.Dir(filePath))
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return func() {
+		_ = syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		f.Close()
+	}, nil
+}
@@ -0,0 +1,144 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+func MergeTodoLists(base, ours, theirs *TodoList) (*TodoList, error) {
+	baseByUID := indexTodos(base)
+	theirsByUID := indexTodos(theirs)
+	seen := make(map[string]bool)
+
+	var todos []Todo
+	for _, o := range ours.Todos {
+		seen[o.UID] = true
+		b, inBase := baseByUID[o.UID]
+		t, inTheirs := theirsByUID[o.UID]
+		switch {
+		case inBase && inTheirs:
+			todo, err := mergeTodo(b, o, t)
+			if err != nil {
+				return nil, err
+			}
+			todos = append(todos, todo)
+		case inBase && sameTodo(b, o):
+			// deleted on their side and untouched on ours
+		default:
+			todos = append(todos, o)
+		}
+	}
+	for _, t := range theirs.Todos {
+		if seen[t.UID] {
+			continue
+		}
+		if b, inBase := baseByUID[t.UID]; inBase && sameTodo(b, t) {
+			continue
+		}
+		todos = append(todos, t)
+	}
+
+	merged := NewTodoList()
+	merged.NextID = max(base.NextID, ours.NextID, theirs.NextID)
+	for _, todo := range todos {
+		merged.NextID = max(merged.NextID, todo.ID+1)
+	}
+	for _, todo := range todos {
+		if _, taken := merged.TodoByID[todo.ID]; taken {
+			todo.ID = merged.NextID
+			merged.NextID++
+		}
+		merged.Todos = append(merged.Todos, todo)
+		merged.TodoByID[todo.ID] = len(merged.Todos) - 1
+	}
+	return merged, nil
+}
+
+// RenumberAdded returns a copy of ours, a list edited from base, in which the
+// tasks added locally whose ID theirs has handed out in the meantime get new
+// IDs. Merged with theirs afterwards, tasks that were already saved keep
+// their IDs.
+func RenumberAdded(base, ours, theirs *TodoList) *TodoList {
+	known := indexTodos(base)
+	renumbered := NewTodoListFrom(ours.Todos)
+	next := max(theirs.NextID, ours.NextID, renumbered.NextID)
+	for i, todo := range renumbered.Todos {
+		if _, ok := known[todo.UID]; !ok && todo.ID < theirs.NextID {
+			renumbered.Todos[i].ID = next
+			next++
+		}
+	}
+	renumbered.NextID = next
+	renumbered.rebuildIndex()
+	return renumbered
+}
+
+func indexTodos(tl *TodoList) map[string]Todo {
+	byUID := make(map[string]Todo, len(tl.Todos))
+	for _, todo := range tl.Todos {
+		byUID[todo.UID] = todo
+	}
+	return byUID
+}
+
+func sameTodo(a, b Todo) bool {
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(ja, jb)
+}
+
+// mergeTodo merges two edited copies of a task field by field. When both
+// sides changed the same field, ours wins.
+func mergeTodo(base, ours, theirs Todo) (Todo, error) {
+	b, err := todoFields(base)
+	if err != nil {
+		return Todo{}, err
+	}
+	o, err := todoFields(ours)
+	if err != nil {
+		return Todo{}, err
+	}
+	t, err := todoFields(theirs)
+	if err != nil {
+		return Todo{}, err
+	}
+	for key, tv := range t {
+		ov, inOurs := o[key]
+		bv, inBase := b[key]
+		oursChanged := inOurs != inBase || !bytes.Equal(ov, bv)
+		theirsChanged := !inBase || !bytes.Equal(tv, bv)
+		if theirsChanged && !oursChanged {
+			o[key] = tv
+		}
+	}
+	for key := range o {
+		if _, inTheirs := t[key]; inTheirs {
+			continue
+		}
+		if bv, inBase := b[key]; inBase && bytes.Equal(o[key], bv) {
+			delete(o, key)
+		}
+	}
+	data, err := json.Marshal(o)
+	if err != nil {
+		return Todo{}, err
+	}
+	var merged Todo
+	if err := json.Unmarshal(data, &merged); err != nil {
+		return Todo{}, err
+	}
+	if theirs.UpdatedAt.After(merged.UpdatedAt) {
+		merged.UpdatedAt = theirs.UpdatedAt
+	}
+	return merged, nil
+}
+
+func todoFields(todo Todo) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(todo)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	err = json.Unmarshal(data, &fields)
+	return fields, err
+}
@@ -0,0 +1,188 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+const SchemaVersion = 10
+
+type Migration struct {
+	From        int
+	Description string
+	apply       func(doc map[string]any) error
+}
+
+var migrations = []Migration{
+	{From: 0, Description: "assign stable UIDs to tasks", apply: migrateAssignUIDs},
+	{From: 1, Description: "fill in missing creation timestamps", apply: migrateFillCreatedAt},
+	{From: 2, Description: "introduce named lists", apply: migrateNoop},
+	{From: 3, Description: "introduce time tracking", apply: migrateNoop},
+	{From: 4, Description: "introduce pomodoros", apply: migrateNoop},
+	{From: 5, Description: "introduce estimates and completion times", apply: migrateNoop},
+	{From: 6, Description: "record modification and archive times", apply: migrateFillUpdatedAt},
+	{From: 7, Description: "introduce due dates, priorities and dependencies", apply: migrateNoop},
+	{From: 8, Description: "introduce daily plans", apply: migrateNoop},
+	{From: 9, Description: "introduce snoozing", apply: migrateNoop},
+}
+
+type NewerSchemaError struct {
+	Version int
+}
+
+func (e NewerSchemaError) Error() string {
+	return fmt.Sprintf("todo file uses schema version %d but this togo only understands up to version %d; upgrade togo before modifying it", e.Version, SchemaVersion)
+}
+
+func PendingMigrations(version int) []Migration {
+	var pending []Migration
+	for _, m := range migrations {
+		if m.From >= version {
+			pending = append(pending, m)
+		}
+	}
+	return pending
+}
+
+func InspectTodoFile(filePath string) (int, error) {
+	data, err := os.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return SchemaVersion, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	_, version, err := decodeDocument(data)
+	return version, err
+}
+
+func decodeDocument(data []byte) (map[string]any, int, error) {
+	doc := make(map[string]any)
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, 0, err
+	}
+	version := 0
+	if n, ok := doc["version"].(json.Number); ok {
+		v, err := n.Int64()
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid schema version %q", n)
+		}
+		version = int(v)
+	}
+	return doc, version, nil
+}
+
+func runMigrations(doc map[string]any, version int) error {
+	for _, m := range PendingMigrations(version) {
+		if err := m.apply(doc); err != nil {
+			return fmt.Errorf("migrating from schema version %d (%s): %w", m.From, m.Description, err)
+		}
+		doc["version"] = m.From + 1
+	}
+	return nil
+}
+
+func documentTodos(doc map[string]any) []map[string]any {
+	raw, _ := doc["todos"].([]any)
+	todos := make([]map[string]any, 0, len(raw))
+	for _, item := range raw {
+		if todo, ok := item.(map[string]any); ok {
+			todos = append(todos, todo)
+		}
+	}
+	return todos
+}
+
+func migrateAssignUIDs(doc map[string]any) error {
+	for _, todo := range documentTodos(doc) {
+		if uid, _ := todo["uid"].(string); uid != "" {
+			continue
+		}
+		id := 0
+		if n, ok := todo["id"].(json.Number); ok {
+			v, err := n.Int64()
+			if err != nil {
+				return err
+			}
+			id = int(v)
+		}
+		var createdAt time.Time
+		if s, ok := todo["created_at"].(string); ok {
+			createdAt, _ = time.Parse(time.RFC3339Nano, s)
+		}
+		todo["uid"] = legacyUID(id, createdAt)
+	}
+	return nil
+}
+
+func migrateFillCreatedAt(doc map[string]any) error {
+	now := time.Now().Format(time.RFC3339Nano)
+	for _, todo := range documentTodos(doc) {
+		s, _ := todo["created_at"].(string)
+		if t, err := time.Parse(time.RFC3339Nano, s); err != nil || t.IsZero() {
+			todo["created_at"] = now
+		}
+	}
+	return nil
+}
+
+// migrateFillUpdatedAt starts the modification time of existing tasks at the
+// latest time known for them.
+func migrateFillUpdatedAt(doc map[string]any) error {
+	for _, todo := range documentTodos(doc) {
+		if s, _ := todo["updated_at"].(string); s != "" {
+			continue
+		}
+		var latest time.Time
+		for _, key := range []string{"created_at", "completed_at"} {
+			s, _ := todo[key].(string)
+			if t, err := time.Parse(time.RFC3339Nano, s); err == nil && t.After(latest) {
+				latest = t
+			}
+		}
+		if !latest.IsZero() {
+			todo["updated_at"] = latest.Format(time.RFC3339Nano)
+		}
+	}
+	return nil
+}
+
+// migrateNoop changes no data; the version bump stops older togo builds,
+// which would drop the fields it introduces, from rewriting the file.
+func migrateNoop(doc map[string]any) error {
+	return nil
+}
+
+func (tl *TodoList) CheckWritable() error {
+	if tl.loadedVersion > SchemaVersion {
+		return NewerSchemaError{Version: tl.loadedVersion}
+	}
+	return nil
+}
+
+func (tl *TodoList) backupBeforeMigration(filePath string) error {
+	if tl.loadedVersion >= SchemaVersion {
+		return nil
+	}
+	data, err := os.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	backup := fmt.Sprintf("%s.v%d.bak", filePath, tl.loadedVersion)
+	if _, err := os.Stat(backup); err == nil {
+		return nil
+	}
+	if err := os.WriteFile(backup, data, 0644); err != nil {
+		return fmt.Errorf("backing up %s before migration: %w", filePath, err)
+	}
+	tl.loadedVersion = SchemaVersion
+	return nil
+}
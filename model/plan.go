@@ -0,0 +1,75 @@
+package model
+
+import "time"
+
+// startOfDay returns midnight at the start of t's day.
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// PlannedOn reports whether the todo is on the plan for day.
+func (t Todo) PlannedOn(day time.Time) bool {
+	return !t.PlannedFor.IsZero() && startOfDay(t.PlannedFor.In(day.Location())).Equal(startOfDay(day))
+}
+
+// PlannedToday reports whether the todo is on the plan for now's day,
+// counting an unfinished todo that rolls over to today from an earlier day.
+func (t Todo) PlannedToday(now time.Time) bool {
+	return t.PlannedOn(now) || t.RollsOver(now)
+}
+
+// RollsOver reports whether the todo is unfinished and planned for a day
+// before now's, so RollOver moves it to today.
+func (t Todo) RollsOver(now time.Time) bool {
+	return !t.Completed && !t.Archived && !t.PlannedFor.IsZero() && t.PlannedFor.Before(startOfDay(now))
+}
+
+// DeferralsAt returns how many days the todo has been deferred by now,
+// counting the days it rolls over to today before RollOver records them.
+func (t Todo) DeferralsAt(now time.Time) int {
+	if !t.RollsOver(now) {
+		return t.Deferrals
+	}
+	return t.Deferrals + daysBetween(t.PlannedFor.In(now.Location()), now)
+}
+
+// daysBetween returns the number of calendar days from from's day to to's,
+// whatever the length of the days in between.
+func daysBetween(from, to time.Time) int {
+	y1, m1, d1 := from.Date()
+	y2, m2, d2 := to.Date()
+	return int(time.Date(y2, m2, d2, 0, 0, 0, 0, time.UTC).Sub(time.Date(y1, m1, d1, 0, 0, 0, 0, time.UTC)) / (24 * time.Hour))
+}
+
+// PlanFor puts a todo on the plan for day; the zero time takes it off.
+func (tl *TodoList) PlanFor(id int, day time.Time) bool {
+	idx := tl.findIndexByID(id)
+	if idx == -1 {
+		return false
+	}
+	if !day.IsZero() {
+		day = startOfDay(day)
+	}
+	if !tl.Todos[idx].PlannedFor.Equal(day) {
+		tl.Todos[idx].PlannedFor = day
+		tl.emitByIndex(ChangeEdited, idx)
+	}
+	return true
+}
+
+// RollOver moves the todos that roll over to today, counting a deferral for
+// every day each one was pushed back, and returns how many it moved. It is
+// bookkeeping rather than an edit: it neither stamps UpdatedAt nor reports
+// changes.
+func (tl *TodoList) RollOver(now time.Time) int {
+	moved := 0
+	for i, todo := range tl.Todos {
+		if todo.RollsOver(now) {
+			tl.Todos[i].Deferrals = todo.DeferralsAt(now)
+			tl.Todos[i].PlannedFor = startOfDay(now)
+			moved++
+		}
+	}
+	return moved
+}
@@ -0,0 +1,193 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Priority ranks tasks from PriorityNone to PriorityHigh.
+type Priority int
+
+const (
+	PriorityNone Priority = iota
+	PriorityLow
+	PriorityMedium
+	PriorityHigh
+)
+
+// ParsePriority reads "high", "medium", "low" or "none", their first
+// letters, or 0-3.
+func ParsePriority(s string) (Priority, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "none", "n", "0", "-":
+		return PriorityNone, nil
+	case "low", "l", "1":
+		return PriorityLow, nil
+	case "medium", "med", "m", "2":
+		return PriorityMedium, nil
+	case "high", "h", "3":
+		return PriorityHigh, nil
+	}
+	return PriorityNone, fmt.Errorf("invalid priority %q (use high, medium, low or none)", s)
+}
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityLow:
+		return "low"
+	case PriorityMedium:
+		return "medium"
+	case PriorityHigh:
+		return "high"
+	}
+	return "none"
+}
+
+// ParseWhen reads a date relative to now: "today", "tomorrow", a weekday
+// ("fri", the next one after today), "next week" (next Monday), a span such
+// as "3d", "2w", "1mo" (a month) or "in 3 days", or a date "2006-01-02",
+// optionally followed by a time "15:04". Without a time the result is the
+// start of the day. "" and "none" give the zero time. A bare "m" is refused,
+// as elsewhere it means minutes.
+func ParseWhen(s string, now time.Time) (time.Time, error) {
+	s = strings.ToLower(strings.Join(strings.Fields(s), " "))
+	if s == "" || s == "none" || s == "-" {
+		return time.Time{}, nil
+	}
+	day, clock := s, ""
+	if i := strings.LastIndexByte(s, ' '); i >= 0 && strings.Contains(s[i+1:], ":") {
+		day, clock = s[:i], s[i+1:]
+	} else if strings.Contains(s, ":") && !strings.Contains(s, "-") {
+		day, clock = "today", s
+	}
+	date, err := parseDay(day, now)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if clock == "" {
+		return date, nil
+	}
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q (use 15:04)", clock)
+	}
+	// Set the clock rather than adding to midnight, which is off by the
+	// shift on the day daylight saving time starts or ends.
+	y, m, d := date.Date()
+	return time.Date(y, m, d, t.Hour(), t.Minute(), 0, 0, date.Location()), nil
+}
+
+var spanUnits = map[string]string{
+	"d": "d", "day": "d", "days": "d",
+	"w": "w", "week": "w", "weeks": "w",
+	"mo": "mo", "month": "mo", "months": "mo",
+	"y": "y", "year": "y", "years": "y",
+}
+
+func parseDay(s string, now time.Time) (time.Time, error) {
+	today := startOfDay(now)
+	switch s {
+	case "today":
+		return today, nil
+	case "tomorrow", "tmr":
+		return today.AddDate(0, 0, 1), nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	case "next week":
+		return today.AddDate(0, 0, 7-(int(today.Weekday())+6)%7), nil
+	}
+	for wd := time.Sunday; wd <= time.Saturday; wd++ {
+		name := strings.ToLower(wd.String())
+		if s == name || s == name[:3] {
+			ahead := (int(wd) - int(today.Weekday()) + 7) % 7
+			if ahead == 0 {
+				ahead = 7
+			}
+			return today.AddDate(0, 0, ahead), nil
+		}
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, now.Location()); err == nil {
+		return t, nil
+	}
+	span := strings.ReplaceAll(strings.TrimPrefix(strings.TrimPrefix(s, "in "), "+"), " ", "")
+	digits := len(span) - len(strings.TrimLeft(span, "0123456789"))
+	if n, err := strconv.Atoi(span[:digits]); err == nil {
+		switch unit := span[digits:]; spanUnits[unit] {
+		case "d":
+			return today.AddDate(0, 0, n), nil
+		case "w":
+			return today.AddDate(0, 0, 7*n), nil
+		case "mo":
+			return today.AddDate(0, n, 0), nil
+		case "y":
+			return today.AddDate(n, 0, 0), nil
+		default:
+			if unit == "m" {
+				return time.Time{}, fmt.Errorf("invalid date %q (m is ambiguous: use %dmo for months)", s, n)
+			}
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q (use today, tomorrow, a weekday, 3d, 2w, 1mo or 2006-01-02)", s)
+}
+
+// HasTime reports whether a due date has a time of day, as opposed to being
+// due some time that day.
+func HasTime(t time.Time) bool {
+	h, m, s := t.Clock()
+	return h != 0 || m != 0 || s != 0
+}
+
+func (tl *TodoList) SetDue(id int, due time.Time) bool {
+	idx := tl.findIndexByID(id)
+	if idx == -1 {
+		return false
+	}
+	if !tl.Todos[idx].Due.Equal(due) {
+		tl.Todos[idx].Due = due
+		tl.emitByIndex(ChangeEdited, idx)
+	}
+	return true
+}
+
+func (tl *TodoList) SetPriority(id int, priority Priority) bool {
+	idx := tl.findIndexByID(id)
+	if idx == -1 {
+		return false
+	}
+	if tl.Todos[idx].Priority != priority {
+		tl.Todos[idx].Priority = priority
+		tl.emitByIndex(ChangeEdited, idx)
+	}
+	return true
+}
+
+// SetBlockedBy records the UIDs of the tasks a todo waits for; nil clears
+// them.
+func (tl *TodoList) SetBlockedBy(id int, uids []string) bool {
+	idx := tl.findIndexByID(id)
+	if idx == -1 {
+		return false
+	}
+	if !slices.Equal(tl.Todos[idx].BlockedBy, uids) {
+		tl.Todos[idx].BlockedBy = slices.Clone(uids)
+		tl.emitByIndex(ChangeEdited, idx)
+	}
+	return true
+}
+
+// Blockers returns the open tasks among those todo waits for. Tasks that
+// are completed or no longer exist do not block it.
+func Blockers(todo Todo, todos []Todo) []Todo {
+	var open []Todo
+	for _, uid := range todo.BlockedBy {
+		for _, t := range todos {
+			if t.UID == uid && !t.Completed {
+				open = append(open, t)
+			}
+		}
+	}
+	return open
+}
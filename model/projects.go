@@ -0,0 +1,132 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+var ErrUnknownProject = errors.New("unknown project")
+
+// Project is an entry in the registry of known project roots, which lets
+// commands operate on a project from any directory.
+type Project struct {
+	Name string `json:"name"`
+	Root string `json:"root"`
+}
+
+func projectsFilePath() (string, error) {
+	dataDir, err := getDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dataDir, "projects.json"), nil
+}
+
+// LoadProjects returns the registered projects sorted by name.
+func LoadProjects() ([]Project, error) {
+	path, err := projectsFilePath()
+	if err != nil {
+		return nil, err
+	}
+	return readProjects(path)
+}
+
+func readProjects(path string) ([]Project, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var projects []Project
+	if err := json.Unmarshal(data, &projects); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return projects, nil
+}
+
+// updateProjects locks the registry, applies fn and saves the result.
+func updateProjects(fn func([]Project) ([]Project, error)) error {
+	path, err := projectsFilePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	unlock, err := lockFile(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	projects, err := readProjects(path)
+	if err != nil {
+		return err
+	}
+	if projects, err = fn(projects); err != nil {
+		return err
+	}
+	slices.SortFunc(projects, func(a, b Project) int { return strings.Compare(a.Name, b.Name) })
+	data, err := json.MarshalIndent(projects, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// RegisterProject adds the project at root to the registry under name, or
+// under the name of its directory when name is empty. Registering a root
+// again returns the existing entry; added reports whether it was new.
+func RegisterProject(root, name string) (project Project, added bool, err error) {
+	if root, err = filepath.Abs(root); err != nil {
+		return Project{}, false, err
+	}
+	if name == "" {
+		name = filepath.Base(root)
+	}
+	err = updateProjects(func(projects []Project) ([]Project, error) {
+		for _, p := range projects {
+			if p.Root == root {
+				project = p
+				return projects, nil
+			}
+		}
+		if slices.ContainsFunc(projects, func(p Project) bool { return strings.EqualFold(p.Name, name) }) {
+			return nil, fmt.Errorf("a project named %q is already registered; choose another name", name)
+		}
+		project, added = Project{Name: name, Root: root}, true
+		return append(projects, project), nil
+	})
+	return project, added, err
+}
+
+// UnregisterProject removes a project from the registry. Its files are kept.
+func UnregisterProject(name string) error {
+	return updateProjects(func(projects []Project) ([]Project, error) {
+		i := slices.IndexFunc(projects, func(p Project) bool { return strings.EqualFold(p.Name, name) })
+		if i < 0 {
+			return nil, fmt.Errorf("%w %q", ErrUnknownProject, name)
+		}
+		return slices.Delete(projects, i, i+1), nil
+	})
+}
+
+// LookupProject finds a registered project by name, ignoring case.
+func LookupProject(name string) (Project, error) {
+	projects, err := LoadProjects()
+	if err != nil {
+		return Project{}, err
+	}
+	for _, p := range projects {
+		if strings.EqualFold(p.Name, name) {
+			return p, nil
+		}
+	}
+	return Project{}, fmt.Errorf("%w %q (see 'togo projects')", ErrUnknownProject, name)
+}
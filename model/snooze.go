@@ -0,0 +1,40 @@
+package model
+
+import (
+	"slices"
+	"time"
+)
+
+// Snoozed reports whether the todo is hidden from the active todos until a
+// time after now.
+func (t Todo) Snoozed(now time.Time) bool {
+	return t.HiddenUntil.After(now)
+}
+
+// Snooze hides a todo from the active todos until the given time; the zero
+// time brings it back.
+func (tl *TodoList) Snooze(id int, until time.Time) bool {
+	idx := tl.findIndexByID(id)
+	if idx == -1 {
+		return false
+	}
+	if !tl.Todos[idx].HiddenUntil.Equal(until) {
+		tl.Todos[idx].HiddenUntil = until
+		tl.emitByIndex(ChangeEdited, idx)
+	}
+	return true
+}
+
+// GetSnoozedTodos returns the unarchived todos that are snoozed, the ones
+// coming back first at the top.
+func (tl *TodoList) GetSnoozedTodos() []Todo {
+	now := time.Now()
+	var snoozed []Todo
+	for _, todo := range tl.Todos {
+		if !todo.Archived && todo.Snoozed(now) {
+			snoozed = append(snoozed, todo)
+		}
+	}
+	slices.SortStableFunc(snoozed, func(a, b Todo) int { return a.HiddenUntil.Compare(b.HiddenUntil) })
+	return snoozed
+}
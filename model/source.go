@@ -0,0 +1,97 @@
+package model
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// projectRoot overrides the project found from the working directory.
+var projectRoot string
+
+// UseProject makes project sources, hooks and everything else that looks for
+// the current project use the project at root instead of the one enclosing the
+// working directory.
+func UseProject(root string) {
+	projectRoot = root
+}
+
+// SearchDir is where project discovery starts: the project set with
+// UseProject, or else the working directory.
+func SearchDir() (string, error) {
+	if projectRoot != "" {
+		return projectRoot, nil
+	}
+	return os.Getwd()
+}
+
+func getDataDir() (string, error) {
+	cacheDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("could not determine user home directory: %w", err)
+	}
+	dataDir := filepath.Join(cacheDir, "togo")
+	return dataDir, nil
+}
+
+// ResolveTodoFilePath returns the todo file of a source. The project source
+// uses the file next to the .togo file of the current project and falls back
+// to the global list when there is none.
+func ResolveTodoFilePath(filename, source string) (string, error) {
+	switch s := strings.ToLower(strings.TrimSpace(source)); s {
+	case "project", "":
+		if root, ok := FindProjectRoot(); ok {
+			return filepath.Join(root, filename), nil
+		}
+	case "global":
+	default:
+		return "", fmt.Errorf("invalid source %q (must be 'project' or 'global')", source)
+	}
+	dataDir, err := getDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dataDir, filename), nil
+}
+
+func findClosestTogoFile() (string, bool) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", false
+	}
+	dir := cwd
+	for {
+		candidate := filepath.Join(dir, ".togo")
+		if isTogoFile(candidate) {
+			return candidate, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	return "", false
+}
+
+// FindProjectRoot returns the directory of the current project: the one set
+// with UseProject, or else the closest directory holding a .togo file.
+func FindProjectRoot() (string, bool) {
+	if projectRoot != "" {
+		return projectRoot, true
+	}
+	togoPath, ok := findClosestTogoFile()
+	if !ok {
+		return "", false
+	}
+	return filepath.Dir(togoPath), true
+}
+
+func GetProjectRootName() (string, bool) {
+	root, ok := FindProjectRoot()
+	if !ok {
+		return "", false
+	}
+	return filepath.Base(root), true
+}
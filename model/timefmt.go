@@ -0,0 +1,125 @@
+package model
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// IsoDateLayout is the absolute time layout used when the locale does not
+// suggest another one.
+const IsoDateLayout = "2006-01-02 15:04"
+
+// TimeStyle says how times are shown: relative to now ("3 days ago") or as
+// dates in Layout, which defaults to the layout of the user's locale.
+type TimeStyle struct {
+	Absolute bool
+	Layout   string
+}
+
+// Format formats t in the style. The zero time is shown as "-".
+func (s TimeStyle) Format(t, now time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	if s.Absolute {
+		return t.In(now.Location()).Format(cmp.Or(s.Layout, LocaleDateLayout()))
+	}
+	return HumanizeTime(t, now)
+}
+
+// FormatTimeAgo describes t relative to the current time.
+func FormatTimeAgo(t time.Time) string {
+	return HumanizeTime(t, time.Now())
+}
+
+// HumanizeTime describes t relative to now in the largest unit that fits,
+// e.g. "just now", "5 minutes ago", "yesterday", "3 weeks ago" or, for times
+// after now, "in 2 days".
+func HumanizeTime(t, now time.Time) string {
+	d := now.Sub(t)
+	future := d < 0
+	if future {
+		d = -d
+	}
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d >= 24*time.Hour && d < 48*time.Hour && future:
+		return "tomorrow"
+	case d >= 24*time.Hour && d < 48*time.Hour:
+		return "yesterday"
+	case future:
+		return "in " + HumanizeDuration(d)
+	}
+	return HumanizeDuration(d) + " ago"
+}
+
+// HumanizeDuration describes d in the largest unit that fits, e.g.
+// "5 minutes", "1 day" or "3 weeks". Months are 30 days and years 365.
+func HumanizeDuration(d time.Duration) string {
+	const day = 24 * time.Hour
+	var n int
+	var unit string
+	switch d = d.Abs(); {
+	case d < time.Minute:
+		return "under a minute"
+	case d < time.Hour:
+		n, unit = int(d/time.Minute), "minute"
+	case d < day:
+		n, unit = int(d/time.Hour), "hour"
+	case d < 7*day:
+		n, unit = int(d/day), "day"
+	case d < 30*day:
+		n, unit = int(d/(7*day)), "week"
+	case d < 365*day:
+		n, unit = int(d/(30*day)), "month"
+	default:
+		n, unit = int(d/(365*day)), "year"
+	}
+	if n != 1 {
+		unit += "s"
+	}
+	return fmt.Sprintf("%d %s", n, unit)
+}
+
+// LocaleDateLayout returns the usual date and time layout for the locale in
+// LC_ALL, LC_TIME or LANG, or IsoDateLayout.
+func LocaleDateLayout() string {
+	for _, env := range []string{"LC_ALL", "LC_TIME", "LANG"} {
+		if locale := os.Getenv(env); locale != "" {
+			return localeDateLayout(locale)
+		}
+	}
+	return IsoDateLayout
+}
+
+func localeDateLayout(locale string) string {
+	locale, _, _ = strings.Cut(locale, ".")
+	locale, _, _ = strings.Cut(locale, "@")
+	lang, region, _ := strings.Cut(strings.ReplaceAll(locale, "-", "_"), "_")
+	lang, region = strings.ToLower(lang), strings.ToUpper(region)
+	switch lang {
+	case "en":
+		switch region {
+		case "US", "PH", "":
+			return "Jan 2, 2006 3:04 PM"
+		case "CA", "ZA":
+			return IsoDateLayout
+		}
+		return "2 Jan 2006 15:04"
+	case "de", "ru", "pl", "cs", "sk", "fi", "nb", "no", "da", "tr", "uk", "ro", "bg", "hr", "sl", "sr":
+		return "02.01.2006 15:04"
+	case "fr", "es", "it", "pt", "el", "ca", "id", "vi", "he", "ar":
+		return "02/01/2006 15:04"
+	case "nl":
+		return "02-01-2006 15:04"
+	case "ja", "zh":
+		return "2006/01/02 15:04"
+	case "ko", "hu":
+		return "2006. 01. 02. 15:04"
+	}
+	return IsoDateLayout
+}
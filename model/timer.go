@@ -0,0 +1,122 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Interval is a span of time spent on a todo. End is zero while the timer is
+// running.
+type Interval struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end,omitzero"`
+}
+
+// Duration returns the length of the interval, counting a running one up to
+// now.
+func (iv Interval) Duration(now time.Time) time.Duration {
+	if iv.End.IsZero() {
+		return now.Sub(iv.Start)
+	}
+	return iv.End.Sub(iv.Start)
+}
+
+// Running reports whether the todo's timer is running.
+func (t Todo) Running() bool {
+	return len(t.TimeLog) > 0 && t.TimeLog[len(t.TimeLog)-1].End.IsZero()
+}
+
+// TimeSpent returns the total time tracked on the todo up to now.
+func (t Todo) TimeSpent(now time.Time) time.Duration {
+	var total time.Duration
+	for _, iv := range t.TimeLog {
+		total += iv.Duration(now)
+	}
+	return total
+}
+
+// RunningTimer returns the todo whose timer is running, if any.
+func (tl *TodoList) RunningTimer() (*Todo, bool) {
+	for i, todo := range tl.Todos {
+		if todo.Running() {
+			return &tl.Todos[i], true
+		}
+	}
+	return nil, false
+}
+
+// StartTimer starts tracking time on a todo at now. It does nothing if the
+// todo's timer is already running; callers make sure no other timer is.
+func (tl *TodoList) StartTimer(id int, now time.Time) bool {
+	idx := tl.findIndexByID(id)
+	if idx == -1 {
+		return false
+	}
+	if !tl.Todos[idx].Running() {
+		tl.Todos[idx].TimeLog = append(tl.Todos[idx].TimeLog, Interval{Start: now})
+		tl.emitByIndex(ChangeEdited, idx)
+	}
+	return true
+}
+
+// StopTimer stops a todo's running timer at now.
+func (tl *TodoList) StopTimer(id int, now time.Time) bool {
+	idx := tl.findIndexByID(id)
+	if idx == -1 {
+		return false
+	}
+	if tl.stopTimer(idx, now) {
+		tl.emitByIndex(ChangeEdited, idx)
+	}
+	return true
+}
+
+func (tl *TodoList) stopTimer(idx int, now time.Time) bool {
+	todo := &tl.Todos[idx]
+	if !todo.Running() {
+		return false
+	}
+	todo.TimeLog[len(todo.TimeLog)-1].End = now
+	return true
+}
+
+// LogPomodoro records a finished pomodoro on a todo.
+func (tl *TodoList) LogPomodoro(id int, start, end time.Time) bool {
+	idx := tl.findIndexByID(id)
+	if idx == -1 {
+		return false
+	}
+	tl.Todos[idx].Pomodoros = append(tl.Todos[idx].Pomodoros, Interval{Start: start, End: end})
+	tl.emitByIndex(ChangeEdited, idx)
+	return true
+}
+
+// FormatDuration formats a tracked duration as e.g. "2h05m", "12m" or "40s".
+func FormatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	h, m, s := int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60
+	switch {
+	case h > 0:
+		return fmt.Sprintf("%dh%02dm", h, m)
+	case m > 0:
+		return fmt.Sprintf("%dm", m)
+	default:
+		return fmt.Sprintf("%ds", s)
+	}
+}
+
+var tagPattern = regexp.MustCompile(`(?:^|\s)#([\p{L}\p{N}_-]+)`)
+
+// Tags returns the #hashtags in a title, lowercased and without the #.
+func Tags(title string) []string {
+	var tags []string
+	for _, m := range tagPattern.FindAllStringSubmatch(title, -1) {
+		if tag := strings.ToLower(m[1]); !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
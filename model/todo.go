@@ -1,76 +1,149 @@
 package model
 
 import (
+	"bytes"
 	"encoding/json"
-	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 )
 
 type Todo struct {
-	ID        int       `json:"id"`
-	Title     string    `json:"title"`
-	Completed bool      `json:"completed"`
-	Archived  bool      `json:"archived"`
-	CreatedAt time.Time `json:"created_at"`
+	ID          int        `json:"id"`
+	UID         string     `json:"uid"`
+	Title       string     `json:"title"`
+	Completed   bool       `json:"completed"`
+	Archived    bool       `json:"archived"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at,omitzero"`
+	CompletedAt time.Time  `json:"completed_at,omitzero"`
+	ArchivedAt  time.Time  `json:"archived_at,omitzero"`
+	Due         time.Time  `json:"due,omitzero"`
+	Priority    Priority   `json:"priority,omitempty"`
+	BlockedBy   []string   `json:"blocked_by,omitempty"`
+	PlannedFor  time.Time  `json:"planned_for,omitzero"`
+	Deferrals   int        `json:"deferrals,omitempty"`
+	HiddenUntil time.Time  `json:"hidden_until,omitzero"`
+	Estimate    Estimate   `json:"estimate,omitzero"`
+	Location    string     `json:"location,omitempty"`
+	List        string     `json:"list,omitempty"`
+	TimeLog     []Interval `json:"time_log,omitempty"`
+	Pomodoros   []Interval `json:"pomodoros,omitempty"`
+	Branches    []string   `json:"branches,omitempty"`
+	Commits     []string   `json:"commits,omitempty"`
 }
 
-func (tl *TodoList) SaveWithSource(filename, source string) error {
-	filePath, err := getTodoFilePathWithSource(filename, source)
+func LoadTodoListWithSource(filename, source string) (*TodoList, error) {
+	filePath, err := ResolveTodoFilePath(filename, source)
+	if err != nil {
+		return nil, err
+	}
+	return LoadTodoListFile(filePath)
+}
+
+func UpdateWithSource(filename, source string, fn func(*TodoList) error) error {
+	filePath, err := ResolveTodoFilePath(filename, source)
 	if err != nil {
 		return err
 	}
+	return UpdateFile(filePath, fn)
+}
+
+// UpdateFile locks the todo file at filePath, loads it, applies fn and saves
+// the result. Nothing is written when fn returns an error.
+func UpdateFile(filePath string, fn func(*TodoList) error) error {
 	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
 		return err
 	}
-	data, err := json.Marshal(tl)
+	unlock, err := lockFile(filePath)
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(filePath, data, 0644)
+	defer unlock()
+	tl, err := LoadTodoListFile(filePath)
+	if err != nil {
+		return err
+	}
+	if err := fn(tl); err != nil {
+		return err
+	}
+	return tl.saveFile(filePath)
 }
 
-func LoadTodoListWithSource(filename, source string) (*TodoList, error) {
-	filePath, err := getTodoFilePathWithSource(filename, source)
+func LoadTodoListFile(filePath string) (*TodoList, error) {
+	if _, err := os.Stat(filePath); os.IsNotExist(err) {
+		return NewTodoList(), nil
+	}
+	data, err := os.ReadFile(filePath)
 	if err != nil {
 		return nil, err
 	}
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
+	return DecodeTodoList(data)
+}
+
+func (tl *TodoList) saveFile(filePath string) error {
+	if err := tl.CheckWritable(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return err
+	}
+	if err := tl.backupBeforeMigration(filePath); err != nil {
+		return err
+	}
+	data, err := tl.Encode()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filePath, data, 0644)
+}
+
+func DecodeTodoList(data []byte) (*TodoList, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
 		return NewTodoList(), nil
 	}
-	data, err := os.ReadFile(filePath)
+	doc, version, err := decodeDocument(data)
 	if err != nil {
 		return nil, err
 	}
+	if version < SchemaVersion {
+		if err := runMigrations(doc, version); err != nil {
+			return nil, err
+		}
+		if data, err = json.Marshal(doc); err != nil {
+			return nil, err
+		}
+	}
 	var tl TodoList
 	if err := json.Unmarshal(data, &tl); err != nil {
 		return nil, err
 	}
-	for i, todo := range tl.Todos {
-		if todo.CreatedAt.IsZero() {
-			tl.Todos[i].CreatedAt = time.Now()
-		}
-	}
-	tl.TodoByID = make(map[int]int)
-	for i, todo := range tl.Todos {
-		tl.TodoByID[todo.ID] = i
+	tl.loadedVersion = version
+	if tl.NextID < 1 {
+		tl.NextID = 1
 	}
+	tl.rebuildIndex()
 	return &tl, nil
 }
 
 type TodoList struct {
-	Todos    []Todo      `json:"todos"`
-	NextID   int         `json:"next_id"`
-	TodoByID map[int]int `json:"-"`
+	Version       int         `json:"version"`
+	Todos         []Todo      `json:"todos"`
+	NextID        int         `json:"next_id"`
+	TodoByID      map[int]int `json:"-"`
+	loadedVersion int
+	listeners     []func(Change)
 }
 
 func NewTodoList() *TodoList {
 	return &TodoList{
-		Todos:    []Todo{},
-		NextID:   1,
-		TodoByID: make(map[int]int),
+		Version:       SchemaVersion,
+		Todos:         []Todo{},
+		NextID:        1,
+		TodoByID:      make(map[int]int),
+		loadedVersion: SchemaVersion,
 	}
 }
 
@@ -82,19 +155,42 @@ func (tl *TodoList) rebuildIndex() {
 }
 
 func (tl *TodoList) Add(title string) *Todo {
+	now := time.Now()
 	todo := Todo{
 		ID:        tl.NextID,
+		UID:       newUID(),
 		Title:     title,
 		Completed: false,
 		Archived:  false,
-		CreatedAt: time.Now(),
+		CreatedAt: now,
+		UpdatedAt: now,
 	}
 	tl.Todos = append(tl.Todos, todo)
 	tl.TodoByID[todo.ID] = len(tl.Todos) - 1
 	tl.NextID++
+	tl.emit(ChangeAdded, todo)
 	return &todo
 }
 
+// Insert adds a copy of todo under the next free ID, keeping its other
+// fields. A new UID is generated when it has none or it is already in use.
+func (tl *TodoList) Insert(todo Todo) *Todo {
+	todo = todo.Clone()
+	todo.ID = tl.NextID
+	if todo.UID == "" || slices.ContainsFunc(tl.Todos, func(t Todo) bool { return t.UID == todo.UID }) {
+		todo.UID = newUID()
+	}
+	todo.UpdatedAt = time.Now()
+	if todo.CreatedAt.IsZero() {
+		todo.CreatedAt = todo.UpdatedAt
+	}
+	tl.Todos = append(tl.Todos, todo)
+	tl.TodoByID[todo.ID] = len(tl.Todos) - 1
+	tl.NextID++
+	tl.emit(ChangeAdded, todo)
+	return &tl.Todos[len(tl.Todos)-1]
+}
+
 func (tl *TodoList) findIndexByID(id int) int {
 	if idx, ok := tl.TodoByID[id]; ok {
 		return idx
@@ -108,191 +204,190 @@ func (tl *TodoList) Edit(id int, newTitle string) bool {
 		return false
 	}
 	tl.Todos[idx].Title = newTitle
+	tl.emitByIndex(ChangeEdited, idx)
 	return true
 }
 
-func (tl *TodoList) Toggle(id int) bool {
+func (tl *TodoList) SetLocation(id int, location string) bool {
 	idx := tl.findIndexByID(id)
 	if idx == -1 {
 		return false
 	}
-	tl.Todos[idx].Completed = !tl.Todos[idx].Completed
+	tl.Todos[idx].Location = location
+	tl.emitByIndex(ChangeEdited, idx)
 	return true
 }
 
-func (tl *TodoList) Archive(id int) bool {
+// SetList moves a todo to a named list. The default list is stored as "".
+func (tl *TodoList) SetList(id int, list string) bool {
 	idx := tl.findIndexByID(id)
 	if idx == -1 {
 		return false
 	}
-	tl.Todos[idx].Archived = true
+	list = NormalizeListName(list)
+	if tl.Todos[idx].List != list {
+		tl.Todos[idx].List = list
+		tl.emitByIndex(ChangeEdited, idx)
+	}
 	return true
 }
 
-func (tl *TodoList) Unarchive(id int) bool {
+func (tl *TodoList) SetCompleted(id int, completed bool) bool {
 	idx := tl.findIndexByID(id)
 	if idx == -1 {
 		return false
 	}
-	tl.Todos[idx].Archived = false
+	if tl.Todos[idx].Completed != completed {
+		tl.setCompleted(idx, completed, time.Now())
+		tl.emitByIndex(ChangeToggled, idx)
+	}
 	return true
 }
 
-func (tl *TodoList) GetActiveTodos() []Todo {
-	var activeTodos []Todo
-	for _, todo := range tl.Todos {
-		if !todo.Archived {
-			activeTodos = append(activeTodos, todo)
-		}
+// stampIf returns the current time when set is true and the zero time
+// otherwise, for timestamps of a state a todo may enter and leave.
+func stampIf(set bool) time.Time {
+	if set {
+		return time.Now()
 	}
-	return activeTodos
+	return time.Time{}
 }
 
-func (tl *TodoList) GetArchivedTodos() []Todo {
-	var archivedTodos []Todo
-	for _, todo := range tl.Todos {
-		if todo.Archived {
-			archivedTodos = append(archivedTodos, todo)
-		}
+// setCompleted updates the completion status and time of a todo, stopping
+// its timer when it is completed.
+func (tl *TodoList) setCompleted(idx int, completed bool, now time.Time) {
+	todo := &tl.Todos[idx]
+	todo.Completed = completed
+	if completed {
+		todo.CompletedAt = now
+		tl.stopTimer(idx, now)
+	} else {
+		todo.CompletedAt = time.Time{}
 	}
-	return archivedTodos
 }
 
-func (tl *TodoList) Delete(id int) bool {
+func (tl *TodoList) LinkBranch(id int, branch string) bool {
 	idx := tl.findIndexByID(id)
 	if idx == -1 {
 		return false
 	}
-	tl.Todos = append(tl.Todos[:idx], tl.Todos[idx+1:]...)
-	tl.rebuildIndex()
+	if !slices.Contains(tl.Todos[idx].Branches, branch) {
+		tl.Todos[idx].Branches = append(tl.Todos[idx].Branches, branch)
+		tl.emitByIndex(ChangeEdited, idx)
+	}
 	return true
 }
 
-func (tl *TodoList) Save(filename string) error {
-	filePath, err := getTodoFilePath(filename)
-	if err != nil {
-		return err
-	}
-	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
-		return err
+func (tl *TodoList) LinkCommit(id int, hash string) bool {
+	idx := tl.findIndexByID(id)
+	if idx == -1 {
+		return false
 	}
-	data, err := json.Marshal(tl)
-	if err != nil {
-		return err
+	if !slices.Contains(tl.Todos[idx].Commits, hash) {
+		tl.Todos[idx].Commits = append(tl.Todos[idx].Commits, hash)
+		tl.emitByIndex(ChangeEdited, idx)
 	}
-	return os.WriteFile(filePath, data, 0644)
+	return true
 }
 
-func LoadTodoList(filename string) (*TodoList, error) {
-	filePath, err := getTodoFilePath(filename)
-	if err != nil {
-		return nil, err
+// Replace overwrites the todo with the same ID, keeping its UID and creation
+// time. It emits an edit only when something actually changed.
+func (tl *TodoList) Replace(todo Todo) bool {
+	idx := tl.findIndexByID(todo.ID)
+	if idx == -1 {
+		return false
 	}
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		return NewTodoList(), nil
+	prev := tl.Todos[idx]
+	todo.UID, todo.CreatedAt, todo.UpdatedAt = prev.UID, prev.CreatedAt, prev.UpdatedAt
+	if todo.Completed != prev.Completed && todo.CompletedAt.Equal(prev.CompletedAt) {
+		todo.CompletedAt = stampIf(todo.Completed)
 	}
-	data, err := os.ReadFile(filePath)
-	if err != nil {
-		return nil, err
+	if todo.Archived != prev.Archived && todo.ArchivedAt.Equal(prev.ArchivedAt) {
+		todo.ArchivedAt = stampIf(todo.Archived)
 	}
-	var tl TodoList
-	if err := json.Unmarshal(data, &tl); err != nil {
-		return nil, err
+	if sameTodo(tl.Todos[idx], todo) {
+		return true
 	}
+	tl.Todos[idx] = todo
+	tl.emitByIndex(ChangeEdited, idx)
+	return true
+}
+
+func (tl *TodoList) FindByBranch(branch string) (*Todo, bool) {
 	for i, todo := range tl.Todos {
-		if todo.CreatedAt.IsZero() {
-			tl.Todos[i].CreatedAt = time.Now()
+		if slices.Contains(todo.Branches, branch) {
+			return &tl.Todos[i], true
 		}
 	}
-	tl.TodoByID = make(map[int]int)
-	for i, todo := range tl.Todos {
-		tl.TodoByID[todo.ID] = i
-	}
-	return &tl, nil
+	return nil, false
 }
 
-func getDataDir() (string, error) {
-	cacheDir, err := os.UserConfigDir()
-	if err != nil {
-		return "", fmt.Errorf("could not determine user home directory: %w", err)
+func (tl *TodoList) Toggle(id int) bool {
+	idx := tl.findIndexByID(id)
+	if idx == -1 {
+		return false
 	}
-	dataDir := filepath.Join(cacheDir, "togo")
-	return dataDir, nil
+	tl.setCompleted(idx, !tl.Todos[idx].Completed, time.Now())
+	tl.emitByIndex(ChangeToggled, idx)
+	return true
 }
 
-func getTodoFilePath(filename string) (string, error) {
-	if togoPath, ok := findClosestTogoFile(); ok {
-		return togoPath, nil
+func (tl *TodoList) Archive(id int) bool {
+	idx := tl.findIndexByID(id)
+	if idx == -1 {
+		return false
 	}
-	dataDir, err := getDataDir()
-	if err != nil {
-		return "", err
+	now := time.Now()
+	tl.Todos[idx].Archived = true
+	tl.Todos[idx].ArchivedAt = now
+	tl.stopTimer(idx, now)
+	tl.emitByIndex(ChangeArchived, idx)
+	return true
+}
+
+func (tl *TodoList) Unarchive(id int) bool {
+	idx := tl.findIndexByID(id)
+	if idx == -1 {
+		return false
 	}
-	return filepath.Join(dataDir, filename), nil
+	tl.Todos[idx].Archived = false
+	tl.Todos[idx].ArchivedAt = time.Time{}
+	tl.emitByIndex(ChangeUnarchived, idx)
+	return true
 }
 
-func getTodoFilePathWithSource(filename, source string) (string, error) {
-	s := strings.ToLower(strings.TrimSpace(source))
-	switch s {
-	case "global":
-		dataDir, err := getDataDir()
-		if err != nil {
-			return "", err
-		}
-		return filepath.Join(dataDir, filename), nil
-	case "project", "":
-		if togoPath, ok := findClosestTogoFile(); ok {
-			projectDir := filepath.Dir(togoPath)
-			return filepath.Join(projectDir, filename), nil
-		}
-		fallthrough
-	default:
-		dataDir, err := getDataDir()
-		if err != nil {
-			return "", err
+// GetActiveTodos returns the todos that are neither archived nor snoozed.
+func (tl *TodoList) GetActiveTodos() []Todo {
+	now := time.Now()
+	var activeTodos []Todo
+	for _, todo := range tl.Todos {
+		if !todo.Archived && !todo.Snoozed(now) {
+			activeTodos = append(activeTodos, todo)
 		}
-		return filepath.Join(dataDir, filename), nil
 	}
+	return activeTodos
 }
 
-func findClosestTogoFile() (string, bool) {
-	cwd, err := os.Getwd()
-	if err != nil {
-		return "", false
-	}
-	dir := cwd
-	for {
-		candidate := filepath.Join(dir, ".togo")
-		if st, err := os.Stat(candidate); err == nil && !st.IsDir() {
-			return candidate, true
-		}
-		parent := filepath.Dir(dir)
-		if parent == dir {
-			break
+func (tl *TodoList) GetArchivedTodos() []Todo {
+	var archivedTodos []Todo
+	for _, todo := range tl.Todos {
+		if todo.Archived {
+			archivedTodos = append(archivedTodos, todo)
 		}
-		dir = parent
 	}
-	return "", false
+	return archivedTodos
 }
 
-func GetProjectRootName() (string, bool) {
-	cwd, err := os.Getwd()
-	if err != nil {
-		return "", false
-	}
-	dir := cwd
-	for {
-		candidate := filepath.Join(dir, ".togo")
-		if st, err := os.Stat(candidate); err == nil && !st.IsDir() {
-			return filepath.Base(dir), true
-		}
-		parent := filepath.Dir(dir)
-		if parent == dir {
-			break
-		}
-		dir = parent
+func (tl *TodoList) Delete(id int) bool {
+	idx := tl.findIndexByID(id)
+	if idx == -1 {
+		return false
 	}
-	return "", false
+	deleted := tl.Todos[idx]
+	tl.Todos = append(tl.Todos[:idx], tl.Todos[idx+1:]...)
+	tl.rebuildIndex()
+	tl.emit(ChangeDeleted, deleted)
+	return true
 }
 
 func (tl *TodoList) GetTodoByID(id int) *Todo {
@@ -329,42 +424,57 @@ func (tl *TodoList) DeleteByTitle(title string, caseSensitive bool) bool {
 		if matches {
 			tl.Todos = append(tl.Todos[:i], tl.Todos[i+1:]...)
 			tl.rebuildIndex()
+			tl.emit(ChangeDeleted, todo)
 			return true
 		}
 	}
 	return false
 }
 
-func (tl *TodoList) GetTodoTitles() []string {
-	titles := make([]string, len(tl.Todos))
+func (tl *TodoList) Clone() *TodoList {
+	clone := &TodoList{
+		Version:       tl.Version,
+		Todos:         make([]Todo, len(tl.Todos)),
+		NextID:        tl.NextID,
+		loadedVersion: tl.loadedVersion,
+	}
 	for i, todo := range tl.Todos {
-		titles[i] = todo.Title
+		clone.Todos[i] = todo.Clone()
 	}
-	return titles
+	clone.rebuildIndex()
+	return clone
 }
 
-func (tl *TodoList) GetActiveAndArchivedTodoTitles() ([]string, []string) {
-	var activeTitles, archivedTitles []string
-	for _, todo := range tl.Todos {
-		if todo.Archived {
-			archivedTitles = append(archivedTitles, todo.Title)
-		} else {
-			activeTitles = append(activeTitles, todo.Title)
-		}
+// Clone returns a copy of the todo that shares no slices with it.
+func (t Todo) Clone() Todo {
+	t.Branches = slices.Clone(t.Branches)
+	t.BlockedBy = slices.Clone(t.BlockedBy)
+	t.Commits = slices.Clone(t.Commits)
+	t.TimeLog = slices.Clone(t.TimeLog)
+	t.Pomodoros = slices.Clone(t.Pomodoros)
+	return t
+}
+
+// NewTodoListFrom builds a list holding copies of todos, numbering new tasks
+// after the highest ID among them.
+func NewTodoListFrom(todos []Todo) *TodoList {
+	tl := NewTodoList()
+	for _, todo := range todos {
+		tl.Todos = append(tl.Todos, todo.Clone())
+		tl.NextID = max(tl.NextID, todo.ID+1)
 	}
-	return activeTitles, archivedTitles
+	tl.rebuildIndex()
+	return tl
 }
 
-func FormatTimeAgo(t time.Time) string {
-	now := time.Now()
-	diff := now.Sub(t)
-	hours := int(diff.Hours())
-	minutes := int(diff.Minutes()) % 60
-	if hours > 0 {
-		return fmt.Sprintf("%dh", hours)
-	} else if minutes > 0 {
-		return fmt.Sprintf("%dm", minutes)
-	} else {
-		return "now"
+// ReplaceAll swaps the contents of tl for those of other, emitting a change
+// for every task that differs.
+func (tl *TodoList) ReplaceAll(other *TodoList) {
+	changes := DiffTodoLists(tl, other)
+	tl.Todos = other.Clone().Todos
+	tl.NextID = max(tl.NextID, other.NextID)
+	tl.rebuildIndex()
+	for _, c := range changes {
+		tl.emit(c.Type, c.Todo)
 	}
 }
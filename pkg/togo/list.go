@@ -0,0 +1,291 @@
+package togo
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/prime-run/togo/hooks"
+	"github.com/prime-run/togo/model"
+)
+
+// List is the view of a todo list inside Client.Update. It is only valid
+// until the update function returns.
+type List struct {
+	ctx   context.Context
+	tl    *model.TodoList
+	hooks *hooks.Runner
+}
+
+// Tasks returns copies of all tasks in the list.
+func (l *List) Tasks() []Task {
+	return copyTasks(l.tl.Todos)
+}
+
+// Task returns a copy of the task with the given ID.
+func (l *List) Task(id int) (Task, bool) {
+	todo := l.tl.GetTodoByID(id)
+	if todo == nil {
+		return Task{}, false
+	}
+	return todo.Clone(), true
+}
+
+// FindByBranch returns the task linked to a git branch.
+func (l *List) FindByBranch(branch string) (Task, bool) {
+	todo, ok := l.tl.FindByBranch(branch)
+	if !ok {
+		return Task{}, false
+	}
+	return todo.Clone(), true
+}
+
+// Add adds a pending task to the default list after running the pre-add
+// hooks, which may rewrite it or reject it.
+func (l *List) Add(title string) (Task, error) {
+	return l.AddTask(Task{Title: title})
+}
+
+// AddTask adds a task using task as a template, for example to put it in a
+// named list. Its ID, UID and creation time are assigned by the list.
+func (l *List) AddTask(task Task) (Task, error) {
+	if err := model.CheckListName(task.List); err != nil {
+		return Task{}, err
+	}
+	todo, err := l.hooks.Add(l.ctx, l.tl, task.Clone())
+	if err != nil {
+		return Task{}, err
+	}
+	return todo.Clone(), nil
+}
+
+// Insert adds a copy of task as it is, for example one taken from another
+// source, under a new ID. Its UID is replaced if it is empty or already used.
+// Hooks are not run.
+func (l *List) Insert(task Task) Task {
+	return l.tl.Insert(task).Clone()
+}
+
+func (l *List) Edit(id int, title string) error {
+	if !l.tl.Edit(id, title) {
+		return notFound(id)
+	}
+	return nil
+}
+
+// Replace overwrites the task with the same ID. Its UID and creation time
+// cannot be changed.
+func (l *List) Replace(task Task) error {
+	if !l.tl.Replace(task.Clone()) {
+		return notFound(task.ID)
+	}
+	return nil
+}
+
+// SetCompleted marks a task completed or pending. Completing a pending task
+// runs the pre-complete hooks first.
+func (l *List) SetCompleted(id int, completed bool) error {
+	todo := l.tl.GetTodoByID(id)
+	if todo == nil {
+		return notFound(id)
+	}
+	if todo.Completed == completed {
+		return nil
+	}
+	if !completed {
+		l.tl.SetCompleted(id, false)
+		return nil
+	}
+	return l.hooks.Mutate(l.ctx, l.tl, hooks.PreComplete, id, func(id int) bool {
+		return l.tl.SetCompleted(id, true)
+	})
+}
+
+func (l *List) Toggle(id int) error {
+	todo := l.tl.GetTodoByID(id)
+	if todo == nil {
+		return notFound(id)
+	}
+	return l.SetCompleted(id, !todo.Completed)
+}
+
+// Archive hides a task from the active list after running the archive hooks.
+func (l *List) Archive(id int) error {
+	todo := l.tl.GetTodoByID(id)
+	if todo == nil {
+		return notFound(id)
+	}
+	if todo.Archived {
+		return nil
+	}
+	return l.hooks.Mutate(l.ctx, l.tl, hooks.Archive, id, l.tl.Archive)
+}
+
+func (l *List) Unarchive(id int) error {
+	if !l.tl.Unarchive(id) {
+		return notFound(id)
+	}
+	return nil
+}
+
+// Delete removes a task after running the delete hooks.
+func (l *List) Delete(id int) error {
+	if l.tl.GetTodoByID(id) == nil {
+		return notFound(id)
+	}
+	return l.hooks.Mutate(l.ctx, l.tl, hooks.Delete, id, l.tl.Delete)
+}
+
+// SetLocation records where in the source code a task comes from.
+func (l *List) SetLocation(id int, location string) error {
+	if !l.tl.SetLocation(id, location) {
+		return notFound(id)
+	}
+	return nil
+}
+
+// SetList moves a task to a named list. DefaultList or "" is the default list.
+func (l *List) SetList(id int, name string) error {
+	if err := model.CheckListName(name); err != nil {
+		return err
+	}
+	if !l.tl.SetList(id, name) {
+		return notFound(id)
+	}
+	return nil
+}
+
+// SetEstimate sets the expected size of a task; the zero Estimate clears it.
+func (l *List) SetEstimate(id int, estimate Estimate) error {
+	if !l.tl.SetEstimate(id, estimate) {
+		return notFound(id)
+	}
+	return nil
+}
+
+// SetDue sets when a task is due; the zero time clears it.
+func (l *List) SetDue(id int, due time.Time) error {
+	if !l.tl.SetDue(id, due) {
+		return notFound(id)
+	}
+	return nil
+}
+
+func (l *List) SetPriority(id int, priority Priority) error {
+	if priority < model.PriorityNone || priority > model.PriorityHigh {
+		return fmt.Errorf("invalid priority %d", priority)
+	}
+	if !l.tl.SetPriority(id, priority) {
+		return notFound(id)
+	}
+	return nil
+}
+
+// Plan puts a task on the plan for day; the zero time takes it off.
+func (l *List) Plan(id int, day time.Time) error {
+	if !l.tl.PlanFor(id, day) {
+		return notFound(id)
+	}
+	return nil
+}
+
+// Snooze hides a task from the active tasks until the given time; the zero
+// time brings it back.
+func (l *List) Snooze(id int, until time.Time) error {
+	if !l.tl.Snooze(id, until) {
+		return notFound(id)
+	}
+	return nil
+}
+
+// SetBlockedBy makes a task wait for the tasks with the given IDs; no IDs
+// clears its blockers.
+func (l *List) SetBlockedBy(id int, blockers ...int) error {
+	if l.tl.GetTodoByID(id) == nil {
+		return notFound(id)
+	}
+	var uids []string
+	for _, b := range blockers {
+		blocker := l.tl.GetTodoByID(b)
+		if blocker == nil {
+			return notFound(b)
+		}
+		if b == id || l.waitsFor(blocker.UID, l.tl.GetTodoByID(id).UID) {
+			return fmt.Errorf("%q cannot block %q: %w", blocker.Title, l.tl.GetTodoByID(id).Title, ErrDependencyCycle)
+		}
+		if !slices.Contains(uids, blocker.UID) {
+			uids = append(uids, blocker.UID)
+		}
+	}
+	l.tl.SetBlockedBy(id, uids)
+	return nil
+}
+
+// waitsFor reports whether the task with UID from is blocked, directly or
+// through other tasks, by the task with UID to.
+func (l *List) waitsFor(from, to string) bool {
+	seen := make(map[string]bool)
+	queue := []string{from}
+	for len(queue) > 0 {
+		uid := queue[0]
+		queue = queue[1:]
+		if uid == to {
+			return true
+		}
+		if seen[uid] {
+			continue
+		}
+		seen[uid] = true
+		for _, todo := range l.tl.Todos {
+			if todo.UID == uid {
+				queue = append(queue, todo.BlockedBy...)
+			}
+		}
+	}
+	return false
+}
+
+// RunningTimer returns the task whose timer is running, if any.
+func (l *List) RunningTimer() (Task, bool) {
+	todo, ok := l.tl.RunningTimer()
+	if !ok {
+		return Task{}, false
+	}
+	return todo.Clone(), true
+}
+
+// StartTimer starts tracking time on a task. Only one timer can run at a
+// time: it fails with ErrTimerRunning while another task's timer runs.
+func (l *List) StartTimer(id int) error {
+	if l.tl.GetTodoByID(id) == nil {
+		return notFound(id)
+	}
+	if running, ok := l.tl.RunningTimer(); ok && running.ID != id {
+		return fmt.Errorf("%w on %q", ErrTimerRunning, running.Title)
+	}
+	l.tl.StartTimer(id, time.Now())
+	return nil
+}
+
+// StopTimer stops a task's timer. It does nothing if the timer is not running.
+func (l *List) StopTimer(id int) error {
+	if !l.tl.StopTimer(id, time.Now()) {
+		return notFound(id)
+	}
+	return nil
+}
+
+func (l *List) LinkBranch(id int, branch string) error {
+	if !l.tl.LinkBranch(id, branch) {
+		return notFound(id)
+	}
+	return nil
+}
+
+func (l *List) LinkCommit(id int, hash string) error {
+	if !l.tl.LinkCommit(id, hash) {
+		return notFound(id)
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package togo
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/prime-run/togo/model"
+)
+
+// All is the source that aggregates every project below the working directory
+// and the global list.
+const All = "all"
+
+// Store is a set of tasks that can be read and synced as a whole. It is
+// implemented by Client and Multi.
+type Store interface {
+	Source() string
+	Paths() []string
+	Tasks(ctx context.Context) ([]Task, error)
+	Sync(ctx context.Context, base, edited []Task) ([]Task, error)
+}
+
+// Multi combines the tasks of several clients into one list. Tasks are given
+// IDs of their own, which stay the same for the lifetime of the Multi, and
+// changes are written back to the file each task came from.
+type Multi struct {
+	clients []*Client
+
+	mu     sync.Mutex
+	origin map[string]int // task UID -> index into clients
+	fileID map[string]int // task UID -> ID in its own file
+	ids    map[string]int // task UID -> combined ID
+	used   map[int]bool
+	nextID int
+}
+
+// OpenAll returns a Multi over the current project (see model.SearchDir),
+// every project nested below it and the global list, in that order. New tasks
+// go to the first of them.
+func OpenAll(opts ...Option) (*Multi, error) {
+	dir, err := model.SearchDir()
+	if err != nil {
+		return nil, err
+	}
+	markers, err := model.FindProjectFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	global, err := Open(Global, opts...)
+	if err != nil {
+		return nil, err
+	}
+	var clients []*Client
+	for _, marker := range markers {
+		path := filepath.Join(filepath.Dir(marker), global.fileName)
+		if path != global.path {
+			clients = append(clients, OpenFile(path, opts...))
+		}
+	}
+	return NewMulti(append(clients, global)...), nil
+}
+
+// NewMulti returns a Multi over clients. New tasks go to the first one.
+func NewMulti(clients ...*Client) *Multi {
+	return &Multi{
+		clients: clients,
+		origin:  make(map[string]int),
+		fileID:  make(map[string]int),
+		ids:     make(map[string]int),
+		used:    make(map[int]bool),
+		nextID:  1,
+	}
+}
+
+func (m *Multi) Source() string {
+	return All
+}
+
+func (m *Multi) Clients() []*Client {
+	return slices.Clone(m.clients)
+}
+
+func (m *Multi) Paths() []string {
+	paths := make([]string, len(m.clients))
+	for i, c := range m.clients {
+		paths[i] = c.path
+	}
+	return paths
+}
+
+// Origin returns the client a task returned by the Multi belongs to.
+func (m *Multi) Origin(task Task) (*Client, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i, ok := m.origin[task.UID]
+	if !ok {
+		return nil, false
+	}
+	return m.clients[i], true
+}
+
+// Tasks returns the tasks of every client, renumbered.
+func (m *Multi) Tasks(ctx context.Context) ([]Task, error) {
+	perClient := make([][]Task, len(m.clients))
+	for i, c := range m.clients {
+		tasks, err := c.Tasks(ctx)
+		if err != nil {
+			return nil, err
+		}
+		perClient[i] = tasks
+	}
+	return m.combine(perClient), nil
+}
+
+// Sync saves a combined list that was edited offline. Every task is synced
+// with the file it came from; tasks added locally go to the first client.
+// Each file is saved on its own, so when one fails the ones before it have
+// already been written.
+func (m *Multi) Sync(ctx context.Context, base, edited []Task) ([]Task, error) {
+	baseBy := m.split(base)
+	editedBy := m.split(edited)
+	perClient := make([][]Task, len(m.clients))
+	for i, c := range m.clients {
+		saved, err := c.Sync(ctx, baseBy[i], editedBy[i])
+		if err != nil {
+			return nil, err
+		}
+		perClient[i] = saved
+	}
+	return m.combine(perClient), nil
+}
+
+// combine concatenates the tasks of each client, giving every task the
+// combined ID it had before or the next free one.
+func (m *Multi) combine(perClient [][]Task) []Task {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var tasks []Task
+	for i, clientTasks := range perClient {
+		for _, task := range clientTasks {
+			m.origin[task.UID] = i
+			m.fileID[task.UID] = task.ID
+			task.ID = m.combinedID(task.UID, 0)
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks
+}
+
+// split maps combined tasks back to the clients they belong to, with the IDs
+// of their files. Tasks the Multi has not seen yet keep their combined ID as
+// long as it is free and are numbered after the tasks of the first client;
+// Client.Sync renumbers them again if that ID is taken on disk.
+func (m *Multi) split(tasks []Task) [][]Task {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	perClient := make([][]Task, len(m.clients))
+	var added []Task
+	next := 1
+	for _, task := range tasks {
+		i, ok := m.origin[task.UID]
+		if !ok {
+			added = append(added, task)
+			continue
+		}
+		m.combinedID(task.UID, task.ID)
+		task.ID = m.fileID[task.UID]
+		if i == 0 {
+			next = max(next, task.ID+1)
+		}
+		perClient[i] = append(perClient[i], task)
+	}
+	for _, task := range added {
+		m.combinedID(task.UID, task.ID)
+		task.ID = next
+		next++
+		perClient[0] = append(perClient[0], task)
+	}
+	return perClient
+}
+
+// combinedID returns the combined ID of a task, assigning want or, if that is
+// taken, the next free one the first time the task is seen.
+func (m *Multi) combinedID(uid string, want int) int {
+	if id, ok := m.ids[uid]; ok {
+		return id
+	}
+	if want <= 0 || m.used[want] {
+		for m.used[m.nextID] {
+			m.nextID++
+		}
+		want = m.nextID
+	}
+	m.ids[uid] = want
+	m.used[want] = true
+	return want
+}
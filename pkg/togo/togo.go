@@ -0,0 +1,472 @@
+// Package togo is the Go API for reading and changing togo todo lists.
+//
+// A Client is bound to one todo file, resolved from a source the same way the
+// togo command does (Open) or given as a path (OpenFile). Reads return copies
+// of the tasks. Every change goes through Update, which locks the file, loads
+// its latest contents, applies the change and saves it, so concurrent togo
+// processes never overwrite each other. Changes run the user's lifecycle
+// hooks and are reported to subscribers once they are saved. A Multi combines
+// several clients, such as every project in a monorepo, into one list.
+//
+//	client, err := togo.Open(togo.Project)
+//	if err != nil {
+//		return err
+//	}
+//	task, err := client.Add(ctx, "Write release notes")
+package togo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prime-run/togo/hooks"
+	"github.com/prime-run/togo/model"
+)
+
+// Task is a single todo item. Values returned by this package are copies.
+type Task = model.Todo
+
+type ChangeType = model.ChangeType
+
+// Estimate is the expected size of a task, in points or as a duration.
+type Estimate = model.Estimate
+
+// Retention and Collection describe the clean-up of Client.Collect.
+type (
+	Retention  = model.Retention
+	Collection = model.Collection
+)
+
+type Priority = model.Priority
+
+const (
+	PriorityNone   = model.PriorityNone
+	PriorityLow    = model.PriorityLow
+	PriorityMedium = model.PriorityMedium
+	PriorityHigh   = model.PriorityHigh
+)
+
+const (
+	Added      = model.ChangeAdded
+	Edited     = model.ChangeEdited
+	Toggled    = model.ChangeToggled
+	Archived   = model.ChangeArchived
+	Unarchived = model.ChangeUnarchived
+	Deleted    = model.ChangeDeleted
+)
+
+const (
+	Project = "project"
+	Global  = "global"
+)
+
+const DefaultFileName = "todos.json"
+
+// DefaultList is the name of the list tasks belong to unless they are put in
+// a named one.
+const DefaultList = model.DefaultList
+
+var (
+	ErrNotFound      = errors.New("task not found")
+	ErrInvalidSource = errors.New("invalid source")
+	ErrTimerRunning  = errors.New("a timer is already running")
+	// ErrDependencyCycle is returned when a task would end up waiting for
+	// itself.
+	ErrDependencyCycle = errors.New("dependency cycle")
+)
+
+// Event describes a change a Client saved.
+type Event struct {
+	Type   ChangeType `json:"type"`
+	Source string     `json:"source"`
+	Task   Task       `json:"todo"`
+}
+
+// PostHookError is returned by Update when the change was saved but one or
+// more post-add or post-complete hooks failed.
+type PostHookError struct {
+	Err error
+}
+
+func (e *PostHookError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PostHookError) Unwrap() error {
+	return e.Err
+}
+
+type Option func(*Client)
+
+// WithFileName overrides the todo file name used when resolving a source.
+func WithFileName(name string) Option {
+	return func(c *Client) { c.fileName = name }
+}
+
+// WithHookOutput sends the stderr of hooks to w. By default it is folded into
+// the error of a failing hook.
+func WithHookOutput(w io.Writer) Option {
+	return func(c *Client) {
+		if c.hooks != nil {
+			c.hooks.Stderr = w
+		}
+	}
+}
+
+// WithoutHooks disables lifecycle hooks for the client.
+func WithoutHooks() Option {
+	return func(c *Client) { c.hooks = nil }
+}
+
+// WithRetention cleans up the tasks by policy whenever the client loads
+// them; see Client.Collect.
+func WithRetention(policy Retention) Option {
+	return func(c *Client) { c.retention = policy }
+}
+
+type Client struct {
+	source    string
+	path      string
+	fileName  string
+	hooks     *hooks.Runner
+	retention Retention
+
+	mu     sync.Mutex
+	subs   map[int]func(Event)
+	nextID int
+}
+
+func newClient(opts []Option) *Client {
+	c := &Client{fileName: DefaultFileName, hooks: hooks.New(nil), subs: make(map[int]func(Event))}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Open returns a client for a source: Project uses the todo file next to the
+// closest .togo file (falling back to the global list), Global uses the
+// list in the user's data directory.
+func Open(source string, opts ...Option) (*Client, error) {
+	c := newClient(opts)
+	switch source = strings.ToLower(strings.TrimSpace(source)); source {
+	case "":
+		c.source = Project
+	case Project, Global:
+		c.source = source
+	default:
+		return nil, fmt.Errorf("%w %q (must be %q or %q)", ErrInvalidSource, source, Project, Global)
+	}
+	path, err := model.ResolveTodoFilePath(c.fileName, c.source)
+	if err != nil {
+		return nil, err
+	}
+	c.path = path
+	return c, nil
+}
+
+// OpenFile returns a client for the todo file at path. Its source is the path.
+func OpenFile(path string, opts ...Option) *Client {
+	c := newClient(opts)
+	c.source = path
+	c.path = path
+	return c
+}
+
+func (c *Client) Source() string {
+	return c.source
+}
+
+func (c *Client) Path() string {
+	return c.path
+}
+
+// Paths returns the todo file of the client, so Client satisfies Store.
+func (c *Client) Paths() []string {
+	return []string{c.path}
+}
+
+// Tasks returns copies of all tasks, archived ones included, in file order.
+func (c *Client) Tasks(ctx context.Context) ([]Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	tl, err := model.LoadTodoListFile(c.path)
+	if err != nil {
+		return nil, err
+	}
+	if c.retention.IsZero() || tl.CheckWritable() != nil || tl.Clone().Collect(c.retention, time.Now()).IsEmpty() {
+		return copyTasks(tl.Todos), nil
+	}
+	if _, err := c.Collect(ctx, c.retention, false); err != nil {
+		return nil, err
+	}
+	if tl, err = model.LoadTodoListFile(c.path); err != nil {
+		return nil, err
+	}
+	return copyTasks(tl.Todos), nil
+}
+
+// PurgeFile returns the gzip-compressed file of JSON lines that tasks purged
+// by Collect are appended to, next to the todo file.
+func (c *Client) PurgeFile() string {
+	return model.PurgeFilePath(c.path)
+}
+
+// Collect archives and purges tasks by policy and returns which. Purged
+// tasks are appended to PurgeFile before the todo file is saved. With dryRun
+// nothing is changed.
+func (c *Client) Collect(ctx context.Context, policy Retention, dryRun bool) (Collection, error) {
+	if dryRun {
+		tl, err := model.LoadTodoListFile(c.path)
+		if err != nil {
+			return Collection{}, err
+		}
+		return tl.Collect(policy, time.Now()), nil
+	}
+	var collected Collection
+	err := c.update(ctx, false, func(l *List) error {
+		collected = l.tl.Collect(policy, time.Now())
+		if collected.IsEmpty() {
+			return nil
+		}
+		if err := l.tl.CheckWritable(); err != nil {
+			return err
+		}
+		return model.AppendPurged(c.PurgeFile(), collected.Purged)
+	})
+	return collected, err
+}
+
+// Task returns a copy of the task with the given ID.
+func (c *Client) Task(ctx context.Context, id int) (Task, error) {
+	tasks, err := c.Tasks(ctx)
+	if err != nil {
+		return Task{}, err
+	}
+	for _, task := range tasks {
+		if task.ID == id {
+			return task, nil
+		}
+	}
+	return Task{}, notFound(id)
+}
+
+// Subscribe registers fn to be called, on the goroutine that made the change,
+// for every change the client saves. The returned function unsubscribes.
+func (c *Client) Subscribe(fn func(Event)) func() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	id := c.nextID
+	c.nextID++
+	c.subs[id] = fn
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		delete(c.subs, id)
+	}
+}
+
+// Update applies fn to the latest version of the list and saves the result
+// while holding the file lock. Nothing is saved when fn returns an error or
+// ctx is cancelled before the save.
+func (c *Client) Update(ctx context.Context, fn func(*List) error) error {
+	return c.update(ctx, true, fn)
+}
+
+func (c *Client) update(ctx context.Context, postHooks bool, fn func(*List) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	var (
+		changes []model.Change
+		saved   *model.TodoList
+	)
+	err := model.UpdateFile(c.path, func(tl *model.TodoList) error {
+		tl.RollOver(time.Now())
+		tl.OnChange(func(change model.Change) { changes = append(changes, change) })
+		if err := fn(&List{ctx: ctx, tl: tl, hooks: c.hooks}); err != nil {
+			return err
+		}
+		saved = tl
+		return ctx.Err()
+	})
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	subs := make([]func(Event), 0, len(c.subs))
+	for _, fn := range c.subs {
+		subs = append(subs, fn)
+	}
+	c.mu.Unlock()
+	for _, change := range changes {
+		event := Event{Type: change.Type, Source: c.source, Task: change.Todo.Clone()}
+		for _, fn := range subs {
+			fn(event)
+		}
+	}
+	if !postHooks {
+		return nil
+	}
+	return c.runPostHooks(ctx, saved, changes)
+}
+
+// runPostHooks runs post-add and post-complete hooks with the tasks as they
+// were saved.
+func (c *Client) runPostHooks(ctx context.Context, saved *model.TodoList, changes []model.Change) error {
+	var errs []error
+	for _, change := range changes {
+		var event hooks.Event
+		switch {
+		case change.Type == model.ChangeAdded:
+			event = hooks.PostAdd
+		case change.Type == model.ChangeToggled && change.Todo.Completed:
+			event = hooks.PostComplete
+		default:
+			continue
+		}
+		task := saved.GetTodoByID(change.Todo.ID)
+		if task == nil || task.UID != change.Todo.UID || (event == hooks.PostComplete && !task.Completed) {
+			continue
+		}
+		if _, err := c.hooks.Run(ctx, event, *task); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return &PostHookError{Err: errors.Join(errs...)}
+	}
+	return nil
+}
+
+// Sync saves a list that was edited offline, such as in the TUI. base is the
+// list as it was read and edited is the result of the local edits; changes
+// other processes saved in the meantime are merged in field by field, with
+// local edits winning conflicts. Tasks added locally are renumbered if their
+// ID was handed out in the meantime. Hooks are not run, as the caller is
+// expected to have run them when the edits were made.
+func (c *Client) Sync(ctx context.Context, base, edited []Task) ([]Task, error) {
+	var merged []Task
+	err := c.update(ctx, false, func(l *List) error {
+		baseList := model.NewTodoListFrom(base)
+		ours := model.RenumberAdded(baseList, model.NewTodoListFrom(edited), l.tl)
+		result, err := model.MergeTodoLists(baseList, ours, l.tl)
+		if err != nil {
+			return err
+		}
+		l.tl.ReplaceAll(result)
+		merged = copyTasks(l.tl.Todos)
+		return nil
+	})
+	return merged, err
+}
+
+// Add adds a task titled title.
+func (c *Client) Add(ctx context.Context, title string) (Task, error) {
+	return c.AddTask(ctx, Task{Title: title})
+}
+
+// AddTask adds a task using task as a template. See List.AddTask.
+func (c *Client) AddTask(ctx context.Context, task Task) (Task, error) {
+	var added Task
+	err := c.Update(ctx, func(l *List) error {
+		var err error
+		added, err = l.AddTask(task)
+		return err
+	})
+	return added, err
+}
+
+// Edit changes the title of a task.
+func (c *Client) Edit(ctx context.Context, id int, title string) (Task, error) {
+	return c.change(ctx, id, func(l *List) error { return l.Edit(id, title) })
+}
+
+// SetCompleted marks a task completed or pending.
+func (c *Client) SetCompleted(ctx context.Context, id int, completed bool) (Task, error) {
+	return c.change(ctx, id, func(l *List) error { return l.SetCompleted(id, completed) })
+}
+
+// Toggle flips a task between pending and completed.
+func (c *Client) Toggle(ctx context.Context, id int) (Task, error) {
+	return c.change(ctx, id, func(l *List) error { return l.Toggle(id) })
+}
+
+// Move puts a task in the named list.
+func (c *Client) Move(ctx context.Context, id int, list string) (Task, error) {
+	return c.change(ctx, id, func(l *List) error { return l.SetList(id, list) })
+}
+
+// SetEstimate sets or clears the estimate of a task.
+func (c *Client) SetEstimate(ctx context.Context, id int, estimate Estimate) (Task, error) {
+	return c.change(ctx, id, func(l *List) error { return l.SetEstimate(id, estimate) })
+}
+
+// Plan puts a task on the plan for day; the zero time takes it off.
+func (c *Client) Plan(ctx context.Context, id int, day time.Time) (Task, error) {
+	return c.change(ctx, id, func(l *List) error { return l.Plan(id, day) })
+}
+
+// Snooze hides a task from the active tasks until the given time; the zero
+// time brings it back.
+func (c *Client) Snooze(ctx context.Context, id int, until time.Time) (Task, error) {
+	return c.change(ctx, id, func(l *List) error { return l.Snooze(id, until) })
+}
+
+// Archive hides a task from the active list.
+func (c *Client) Archive(ctx context.Context, id int) (Task, error) {
+	return c.change(ctx, id, func(l *List) error { return l.Archive(id) })
+}
+
+// Unarchive returns an archived task to the active list.
+func (c *Client) Unarchive(ctx context.Context, id int) (Task, error) {
+	return c.change(ctx, id, func(l *List) error { return l.Unarchive(id) })
+}
+
+// Delete removes a task for good.
+func (c *Client) Delete(ctx context.Context, id int) error {
+	return c.Update(ctx, func(l *List) error { return l.Delete(id) })
+}
+
+// change applies fn and returns the task with the given ID as it was saved.
+func (c *Client) change(ctx context.Context, id int, fn func(*List) error) (Task, error) {
+	var task Task
+	err := c.Update(ctx, func(l *List) error {
+		if err := fn(l); err != nil {
+			return err
+		}
+		task, _ = l.Task(id)
+		return nil
+	})
+	return task, err
+}
+
+// ParseEstimate reads an estimate such as "3", "2.5pt" or "2h30m".
+func ParseEstimate(s string) (Estimate, error) {
+	return model.ParseEstimate(s)
+}
+
+// ListNames returns the names of the lists used by tasks, the default list
+// first.
+func ListNames(tasks []Task) []string {
+	return model.ListNames(tasks)
+}
+
+func copyTasks(todos []model.Todo) []Task {
+	tasks := make([]Task, len(todos))
+	for i, todo := range todos {
+		tasks[i] = todo.Clone()
+	}
+	return tasks
+}
+
+func notFound(id int) error {
+	return fmt.Errorf("%w: id %d", ErrNotFound, id)
+}
@@ -0,0 +1,70 @@
+package togo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var ErrSameSource = errors.New("source and destination are the same")
+
+// MoveTo moves tasks to the list of dst. They keep every field, including
+// their UID, except the ID, which dst assigns. The tasks are written to dst
+// before they are removed from c, so an interrupted move leaves a duplicate
+// rather than losing a task. Transfers do not run lifecycle hooks.
+func (c *Client) MoveTo(ctx context.Context, dst *Client, ids ...int) ([]Task, error) {
+	if dst.path == c.path {
+		return nil, ErrSameSource
+	}
+	return c.transfer(ctx, dst, ids, true)
+}
+
+// CopyTo copies tasks to the list of dst, which may be c itself. The copies
+// get new IDs and UIDs.
+func (c *Client) CopyTo(ctx context.Context, dst *Client, ids ...int) ([]Task, error) {
+	return c.transfer(ctx, dst, ids, false)
+}
+
+func (c *Client) transfer(ctx context.Context, dst *Client, ids []int, move bool) ([]Task, error) {
+	all, err := c.Tasks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tasks := make([]Task, 0, len(ids))
+	for _, id := range ids {
+		i := slices.IndexFunc(all, func(t Task) bool { return t.ID == id })
+		if i < 0 {
+			return nil, notFound(id)
+		}
+		task := all[i]
+		if !move {
+			task.UID = ""
+		}
+		tasks = append(tasks, task)
+	}
+
+	var added []Task
+	err = dst.update(ctx, false, func(l *List) error {
+		added = added[:0]
+		for _, task := range tasks {
+			added = append(added, l.Insert(task))
+		}
+		return nil
+	})
+	if err != nil || !move {
+		return added, err
+	}
+	err = c.update(ctx, false, func(l *List) error {
+		for _, task := range tasks {
+			if current, ok := l.Task(task.ID); ok && current.UID == task.UID {
+				l.tl.Delete(task.ID)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return added, fmt.Errorf("tasks were copied to %s but not removed from %s: %w", dst.source, c.source, err)
+	}
+	return added, nil
+}
@@ -0,0 +1,124 @@
+package report
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/prime-run/togo/config"
+	"github.com/prime-run/togo/model"
+)
+
+// Agenda section names, in the order the sections are listed.
+const (
+	Overdue  = "Overdue"
+	Today    = "Today"
+	ThisWeek = "This week"
+	Later    = "Later"
+	NoDate   = "No date"
+)
+
+var sectionOrder = []string{Overdue, Today, ThisWeek, Later, NoDate}
+
+// Ranked is an open task with its score. Parts holds the weighted
+// contribution of each factor to Score.
+type Ranked struct {
+	Todo     model.Todo
+	Blockers []model.Todo
+	Score    float64
+	Parts    config.Scoring
+}
+
+type AgendaSection struct {
+	Name  string
+	Tasks []Ranked
+}
+
+// Rank scores the open tasks among todos that are not snoozed and sorts
+// them, most important first.
+func Rank(todos []model.Todo, now time.Time, weights config.Scoring) []Ranked {
+	var ranked []Ranked
+	for _, todo := range todos {
+		if todo.Completed || todo.Archived || todo.Snoozed(now) {
+			continue
+		}
+		r := Ranked{Todo: todo, Blockers: model.Blockers(todo, todos)}
+		r.Parts = config.Scoring{
+			Priority: weights.Priority * float64(todo.Priority) / float64(model.PriorityHigh),
+			Due:      weights.Due * dueScore(todo, now),
+			Age:      weights.Age * min(now.Sub(todo.CreatedAt).Hours()/24, 30) / 30,
+		}
+		if len(r.Blockers) > 0 {
+			r.Parts.Blocked = weights.Blocked
+		}
+		r.Score = r.Parts.Priority + r.Parts.Due + r.Parts.Age + r.Parts.Blocked
+		ranked = append(ranked, r)
+	}
+	slices.SortStableFunc(ranked, func(a, b Ranked) int {
+		return cmp.Or(cmp.Compare(b.Score, a.Score), cmp.Compare(a.Todo.ID, b.Todo.ID))
+	})
+	return ranked
+}
+
+// Next returns the most important open task that is not blocked.
+func Next(todos []model.Todo, now time.Time, weights config.Scoring) (Ranked, bool) {
+	for _, r := range Rank(todos, now, weights) {
+		if len(r.Blockers) == 0 {
+			return r, true
+		}
+	}
+	return Ranked{}, false
+}
+
+// BuildAgenda groups the open tasks by due date into the sections that have
+// any, each ordered by score.
+func BuildAgenda(todos []model.Todo, now time.Time, weights config.Scoring) []AgendaSection {
+	bySection := make(map[string][]Ranked)
+	for _, r := range Rank(todos, now, weights) {
+		name := section(r.Todo, now)
+		bySection[name] = append(bySection[name], r)
+	}
+	var sections []AgendaSection
+	for _, name := range sectionOrder {
+		if tasks := bySection[name]; len(tasks) > 0 {
+			sections = append(sections, AgendaSection{Name: name, Tasks: tasks})
+		}
+	}
+	return sections
+}
+
+func section(todo model.Todo, now time.Time) string {
+	switch {
+	case todo.Due.IsZero():
+		return NoDate
+	case Deadline(todo.Due).Before(now):
+		return Overdue
+	case todo.Due.Before(StartOfDay(now).AddDate(0, 0, 1)):
+		return Today
+	case todo.Due.Before(StartOfWeek(now).AddDate(0, 0, 7)):
+		return ThisWeek
+	}
+	return Later
+}
+
+// Deadline is the moment a task due at due becomes overdue: that time, or
+// the end of the day for a due date without a time.
+func Deadline(due time.Time) time.Time {
+	if model.HasTime(due) {
+		return due
+	}
+	return due.AddDate(0, 0, 1)
+}
+
+// dueScore is 0 for tasks due in two weeks or more or without a due date,
+// grows to 1 at the deadline and to 2 when two weeks overdue.
+func dueScore(todo model.Todo, now time.Time) float64 {
+	if todo.Due.IsZero() {
+		return 0
+	}
+	days := Deadline(todo.Due).Sub(now).Hours() / 24
+	if days >= 0 {
+		return max(0, 14-days) / 14
+	}
+	return 1 + min(-days, 14)/14
+}
@@ -0,0 +1,116 @@
+package report
+
+import (
+	"time"
+
+	"github.com/prime-run/togo/model"
+)
+
+// Unit is what a burndown measures work in.
+type Unit string
+
+const (
+	UnitPoints Unit = "points"
+	UnitHours  Unit = "hours"
+	UnitTasks  Unit = "tasks"
+)
+
+// Work adds up estimates. Tasks counts every task, estimated or not.
+type Work struct {
+	Points float64
+	Time   time.Duration
+	Tasks  int
+}
+
+func (w *Work) add(todo model.Todo) {
+	w.Points += todo.Estimate.Points
+	w.Time += todo.Estimate.Duration()
+	w.Tasks++
+}
+
+// In returns the amount of work in unit.
+func (w Work) In(unit Unit) float64 {
+	switch unit {
+	case UnitPoints:
+		return w.Points
+	case UnitHours:
+		return w.Time.Hours()
+	}
+	return float64(w.Tasks)
+}
+
+// BurndownDay is the work left at the end of a day and the work completed on
+// it.
+type BurndownDay struct {
+	Date      time.Time
+	Remaining Work
+	Completed Work
+}
+
+type Burndown struct {
+	From, To time.Time
+	Days     []BurndownDay
+	// Estimated is the work of every task in scope: those created before To
+	// that were not completed before From. Completed is the part of it
+	// completed since From.
+	Estimated   Work
+	Completed   Work
+	Unestimated int
+	// Unknown counts completed tasks left out because they were completed
+	// before completion times were recorded.
+	Unknown int
+}
+
+// BuildBurndown follows the work remaining each day from the day of from up
+// to now. Archived tasks that were never completed are out of scope.
+func BuildBurndown(todos []model.Todo, from, now time.Time) Burndown {
+	from = StartOfDay(from)
+	b := Burndown{From: from, To: now}
+	var scope []model.Todo
+	for _, todo := range todos {
+		switch {
+		case todo.CreatedAt.After(now):
+		case todo.Completed && todo.CompletedAt.IsZero():
+			b.Unknown++
+		case todo.Completed && todo.CompletedAt.Before(from):
+		case todo.Archived && !todo.Completed:
+		default:
+			scope = append(scope, todo)
+			b.Estimated.add(todo)
+			if todo.Estimate.IsZero() {
+				b.Unestimated++
+			}
+			if todo.Completed {
+				b.Completed.add(todo)
+			}
+		}
+	}
+	for day := from; day.Before(now); day = day.AddDate(0, 0, 1) {
+		end := earlier(day.AddDate(0, 0, 1), now)
+		d := BurndownDay{Date: day}
+		for _, todo := range scope {
+			if !todo.CreatedAt.Before(end) {
+				continue
+			}
+			if !todo.Completed || todo.CompletedAt.After(end) {
+				d.Remaining.add(todo)
+			} else if !todo.CompletedAt.Before(day) {
+				d.Completed.add(todo)
+			}
+		}
+		b.Days = append(b.Days, d)
+	}
+	return b
+}
+
+// DefaultUnit picks points when any task is estimated in points, hours when
+// any is estimated in time and tasks otherwise.
+func (b Burndown) DefaultUnit() Unit {
+	switch {
+	case b.Estimated.Points > 0:
+		return UnitPoints
+	case b.Estimated.Time > 0:
+		return UnitHours
+	}
+	return UnitTasks
+}
@@ -0,0 +1,40 @@
+package report
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/prime-run/togo/model"
+)
+
+// TaskPomodoros is the number of pomodoros finished on one task.
+type TaskPomodoros struct {
+	Todo  model.Todo
+	Count int
+	Focus time.Duration
+}
+
+// SummarizePomodoros counts the pomodoros finished on each todo between from
+// and to, most first. A zero from counts every pomodoro before to.
+func SummarizePomodoros(todos []model.Todo, from, to time.Time) []TaskPomodoros {
+	var out []TaskPomodoros
+	for _, todo := range todos {
+		sum := TaskPomodoros{Todo: todo}
+		for _, p := range todo.Pomodoros {
+			if p.End.Before(from) || !p.End.Before(to) {
+				continue
+			}
+			sum.Count++
+			sum.Focus += p.End.Sub(p.Start)
+		}
+		if sum.Count > 0 {
+			out = append(out, sum)
+		}
+	}
+	slices.SortFunc(out, func(a, b TaskPomodoros) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), strings.Compare(a.Todo.Title, b.Todo.Title))
+	})
+	return out
+}
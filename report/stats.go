@@ -0,0 +1,183 @@
+package report
+
+import (
+	"cmp"
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/prime-run/togo/model"
+)
+
+// Stats summarizes a todo list: how much is open and done, how fast tasks
+// get completed and on which days.
+type Stats struct {
+	Open     int `json:"open"`
+	Done     int `json:"done"`
+	Archived int `json:"archived"`
+	// CompletionRate is the share of unarchived and completed tasks that
+	// are done.
+	CompletionRate       float64       `json:"completion_rate"`
+	AvgTimeToComplete    time.Duration `json:"-"`
+	MedianTimeToComplete time.Duration `json:"-"`
+	Weeks                []WeekStats   `json:"weeks"`
+	Tags                 []TagStats    `json:"tags"`
+	Oldest               []model.Todo  `json:"oldest_open"`
+	// Days holds the completions of every day of the heatmap, which starts
+	// on a Monday and ends today.
+	Days          []DayCount `json:"days"`
+	CurrentStreak int        `json:"current_streak"`
+	LongestStreak int        `json:"longest_streak"`
+}
+
+// WeekStats counts the tasks created and completed in the week from Start.
+type WeekStats struct {
+	Start     time.Time `json:"start"`
+	Created   int       `json:"created"`
+	Completed int       `json:"completed"`
+}
+
+type TagStats struct {
+	Tag  string `json:"tag"`
+	Open int    `json:"open"`
+	Done int    `json:"done"`
+}
+
+type DayCount struct {
+	Date      time.Time `json:"date"`
+	Completed int       `json:"completed"`
+}
+
+// OldestCount is how many open tasks Stats lists in Oldest.
+const OldestCount = 5
+
+// BuildStats computes the stats of todos as of now, with weeks weeks of
+// history for the weekly counts and the heatmap.
+func BuildStats(todos []model.Todo, now time.Time, weeks int) Stats {
+	var s Stats
+	from := StartOfWeek(now).AddDate(0, 0, -7*(weeks-1))
+	s.Weeks = make([]WeekStats, weeks)
+	for i := range s.Weeks {
+		s.Weeks[i].Start = from.AddDate(0, 0, 7*i)
+	}
+	for day := from; !day.After(now); day = day.AddDate(0, 0, 1) {
+		s.Days = append(s.Days, DayCount{Date: day})
+	}
+
+	tags := make(map[string]*TagStats)
+	completedOn := make(map[time.Time]int)
+	var durations []time.Duration
+	var open []model.Todo
+	for _, todo := range todos {
+		switch {
+		case todo.Completed:
+			s.Done++
+		case todo.Archived:
+			s.Archived++
+			continue
+		default:
+			s.Open++
+			open = append(open, todo)
+		}
+		for _, tag := range model.Tags(todo.Title) {
+			if tags[tag] == nil {
+				tags[tag] = &TagStats{Tag: tag}
+			}
+			if todo.Completed {
+				tags[tag].Done++
+			} else {
+				tags[tag].Open++
+			}
+		}
+		if i := weekIndex(from, todo.CreatedAt, weeks); i >= 0 {
+			s.Weeks[i].Created++
+		}
+		if !todo.Completed || todo.CompletedAt.IsZero() {
+			continue
+		}
+		durations = append(durations, todo.CompletedAt.Sub(todo.CreatedAt))
+		completedOn[StartOfDay(todo.CompletedAt.In(now.Location()))]++
+		if i := weekIndex(from, todo.CompletedAt, weeks); i >= 0 {
+			s.Weeks[i].Completed++
+		}
+	}
+	if s.Open+s.Done > 0 {
+		s.CompletionRate = float64(s.Done) / float64(s.Open+s.Done)
+	}
+	if len(durations) > 0 {
+		var total time.Duration
+		for _, d := range durations {
+			total += d
+		}
+		s.AvgTimeToComplete = total / time.Duration(len(durations))
+		slices.Sort(durations)
+		s.MedianTimeToComplete = durations[len(durations)/2]
+		if len(durations)%2 == 0 {
+			s.MedianTimeToComplete = (durations[len(durations)/2-1] + durations[len(durations)/2]) / 2
+		}
+	}
+	for i := range s.Days {
+		s.Days[i].Completed = completedOn[s.Days[i].Date]
+	}
+	s.CurrentStreak, s.LongestStreak = streaks(completedOn, StartOfDay(now))
+
+	for _, t := range tags {
+		s.Tags = append(s.Tags, *t)
+	}
+	slices.SortFunc(s.Tags, func(a, b TagStats) int {
+		return cmp.Or(cmp.Compare(b.Open+b.Done, a.Open+a.Done), strings.Compare(a.Tag, b.Tag))
+	})
+	slices.SortStableFunc(open, func(a, b model.Todo) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	s.Oldest = open[:min(len(open), OldestCount)]
+	return s
+}
+
+func (s Stats) MarshalJSON() ([]byte, error) {
+	type stats Stats
+	return json.Marshal(struct {
+		stats
+		AvgSeconds    float64 `json:"avg_time_to_complete_seconds"`
+		MedianSeconds float64 `json:"median_time_to_complete_seconds"`
+	}{stats(s), s.AvgTimeToComplete.Seconds(), s.MedianTimeToComplete.Seconds()})
+}
+
+func weekIndex(from, t time.Time, weeks int) int {
+	if t.Before(from) {
+		return -1
+	}
+	i := int(StartOfDay(t.In(from.Location())).Sub(from).Hours()+12) / (7 * 24)
+	if i >= weeks {
+		return -1
+	}
+	return i
+}
+
+// streaks returns the number of consecutive days with completions up to
+// today, or up to yesterday while today has none yet, and the longest such
+// run.
+func streaks(completedOn map[time.Time]int, today time.Time) (current, longest int) {
+	days := make([]time.Time, 0, len(completedOn))
+	for day := range completedOn {
+		days = append(days, day)
+	}
+	slices.SortFunc(days, time.Time.Compare)
+	run := 0
+	for i, day := range days {
+		if i > 0 && days[i-1].AddDate(0, 0, 1).Equal(day) {
+			run++
+		} else {
+			run = 1
+		}
+		longest = max(longest, run)
+	}
+	day := today
+	if completedOn[day] == 0 {
+		day = day.AddDate(0, 0, -1)
+	}
+	for completedOn[day] > 0 {
+		current++
+		day = day.AddDate(0, 0, -1)
+	}
+	return current, longest
+}
@@ -0,0 +1,122 @@
+// Package report computes summaries of todo lists for the reporting commands.
+package report
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/prime-run/togo/model"
+)
+
+// Untagged is the tag time is booked under for tasks without #tags.
+const Untagged = ""
+
+// TagTime is the time spent on tasks carrying a tag.
+type TagTime struct {
+	Tag      string
+	Duration time.Duration
+}
+
+// Day is the time tracked on one calendar day. A task with several tags
+// counts towards each of them, but only once towards Total.
+type Day struct {
+	Date  time.Time
+	Total time.Duration
+	Tags  []TagTime
+}
+
+type Timesheet struct {
+	From, To time.Time
+	Days     []Day
+	Total    time.Duration
+	Tags     []TagTime
+}
+
+// BuildTimesheet adds up the time tracked on todos between from and to, split
+// by day in the location of from. Running timers count up to now.
+func BuildTimesheet(todos []model.Todo, from, to, now time.Time) Timesheet {
+	sheet := Timesheet{From: from, To: to}
+	days := make(map[time.Time]map[string]time.Duration)
+	totals := make(map[time.Time]time.Duration)
+	weekTags := make(map[string]time.Duration)
+	for _, todo := range todos {
+		tags := model.Tags(todo.Title)
+		if len(tags) == 0 {
+			tags = []string{Untagged}
+		}
+		for _, iv := range todo.TimeLog {
+			start, end := iv.Start, iv.End
+			if end.IsZero() {
+				end = now
+			}
+			start, end = later(start, from), earlier(end, to)
+			for start.Before(end) {
+				day := StartOfDay(start.In(from.Location()))
+				next := earlier(day.AddDate(0, 0, 1), end)
+				d := next.Sub(start)
+				if days[day] == nil {
+					days[day] = make(map[string]time.Duration)
+				}
+				for _, tag := range tags {
+					days[day][tag] += d
+					weekTags[tag] += d
+				}
+				totals[day] += d
+				sheet.Total += d
+				start = next
+			}
+		}
+	}
+	for day, tags := range days {
+		sheet.Days = append(sheet.Days, Day{Date: day, Total: totals[day], Tags: sortTags(tags)})
+	}
+	slices.SortFunc(sheet.Days, func(a, b Day) int { return a.Date.Compare(b.Date) })
+	sheet.Tags = sortTags(weekTags)
+	return sheet
+}
+
+// StartOfDay returns midnight at the start of t's day.
+func StartOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// StartOfWeek returns midnight on the Monday of t's week.
+func StartOfWeek(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	return StartOfDay(t).AddDate(0, 0, -offset)
+}
+
+// sortTags orders tags by time spent, longest first, with untagged time last.
+func sortTags(tags map[string]time.Duration) []TagTime {
+	var out []TagTime
+	for tag, d := range tags {
+		out = append(out, TagTime{Tag: tag, Duration: d})
+	}
+	slices.SortFunc(out, func(a, b TagTime) int {
+		if (a.Tag == Untagged) != (b.Tag == Untagged) {
+			if a.Tag == Untagged {
+				return 1
+			}
+			return -1
+		}
+		return cmp.Or(cmp.Compare(b.Duration, a.Duration), strings.Compare(a.Tag, b.Tag))
+	})
+	return out
+}
+
+func later(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func earlier(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
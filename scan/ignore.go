@@ -0,0 +1,156 @@
+package scan
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+type ignorePattern struct {
+	re       *regexp.Regexp
+	negate   bool
+	dirOnly  bool
+	anchored bool
+}
+
+type ignoreMatcher struct {
+	root     string
+	patterns map[string][]ignorePattern
+}
+
+func newIgnoreMatcher(root string) *ignoreMatcher {
+	return &ignoreMatcher{root: root, patterns: make(map[string][]ignorePattern)}
+}
+
+func (m *ignoreMatcher) load(dir string) []ignorePattern {
+	if patterns, ok := m.patterns[dir]; ok {
+		return patterns
+	}
+	var patterns []ignorePattern
+	f, err := os.Open(filepath.Join(m.root, filepath.FromSlash(dir), ".gitignore"))
+	if err == nil {
+		sc := bufio.NewScanner(f)
+		for sc.Scan() {
+			if p, ok := parseIgnorePattern(sc.Text()); ok {
+				patterns = append(patterns, p)
+			}
+		}
+		f.Close()
+	}
+	m.patterns[dir] = patterns
+	return patterns
+}
+
+// Ignored reports whether rel (slash-separated, relative to the root) is
+// excluded by any .gitignore between the root and its parent directory.
+func (m *ignoreMatcher) Ignored(rel string, isDir bool) bool {
+	if rel == "." || rel == "" {
+		return false
+	}
+	if path.Base(rel) == ".git" {
+		return true
+	}
+	ignored := false
+	dir := "."
+	parts := strings.Split(path.Dir(rel), "/")
+	if parts[0] == "." {
+		parts = nil
+	}
+	for i := 0; i <= len(parts); i++ {
+		if i > 0 {
+			dir = path.Join(dir, parts[i-1])
+		}
+		sub := rel
+		if dir != "." {
+			sub = strings.TrimPrefix(rel, dir+"/")
+		}
+		for _, p := range m.load(dir) {
+			if p.dirOnly && !isDir {
+				continue
+			}
+			target := sub
+			if !p.anchored {
+				target = path.Base(sub)
+			}
+			if p.re.MatchString(target) {
+				ignored = !p.negate
+			}
+		}
+	}
+	return ignored
+}
+
+func parseIgnorePattern(line string) (ignorePattern, bool) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ignorePattern{}, false
+	}
+	var p ignorePattern
+	if strings.HasPrefix(line, "!") {
+		p.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\`) {
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		p.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if strings.Contains(line, "/") {
+		p.anchored = true
+		line = strings.TrimPrefix(line, "/")
+	}
+	if line == "" {
+		return ignorePattern{}, false
+	}
+	re, err := regexp.Compile("^" + globToRegexp(line) + "$")
+	if err != nil {
+		return ignorePattern{}, false
+	}
+	p.re = re
+	return p, true
+}
+
+func globToRegexp(glob string) string {
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			b.WriteString("(.*/)?")
+			i += 2
+		case glob[i:] == "/**":
+			// "dir/**" matches everything inside dir, but not dir itself.
+			b.WriteString("/.*")
+			i += 2
+		case strings.HasPrefix(glob[i:], "/**"):
+			b.WriteString("(/.*)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(glob[i:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i += end
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,105 @@
+package scan
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIgnoreMatcher(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		".gitignore": `# build output
+*.log
+!keep.log
+/bin
+build/
+docs/*.html
+**/generated
+vendor/**
+!vendor/patched.go
+\#notes
+data?.csv
+tmp[0-9]
+`,
+		"sub/.gitignore": `local.txt
+/only-here
+!important.log
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{path: "main.go", want: false},
+		{path: ".git", isDir: true, want: true},
+		{path: "sub/.git", isDir: true, want: true},
+
+		{path: "debug.log", want: true},
+		{path: "sub/deep/debug.log", want: true},
+		{path: "keep.log", want: false},
+
+		{path: "bin", isDir: true, want: true},
+		{path: "sub/bin", isDir: true, want: false},
+
+		{path: "build", isDir: true, want: true},
+		{path: "sub/build", isDir: true, want: true},
+		{path: "build", isDir: false, want: false},
+
+		{path: "docs/index.html", want: true},
+		{path: "docs/api/index.html", want: false},
+		{path: "sub/docs/index.html", want: false},
+
+		{path: "generated", isDir: true, want: true},
+		{path: "a/b/generated", isDir: true, want: true},
+
+		{path: "vendor", isDir: true, want: false},
+		{path: "vendor/lib/x.go", want: true},
+		{path: "vendor/patched.go", want: false},
+
+		{path: "#notes", want: true},
+		{path: "notes", want: false},
+
+		{path: "data1.csv", want: true},
+		{path: "data12.csv", want: false},
+		{path: "tmp7", want: true},
+		{path: "tmpx", want: false},
+
+		{path: "sub/local.txt", want: true},
+		{path: "local.txt", want: false},
+		{path: "sub/only-here", want: true},
+		{path: "sub/x/only-here", want: false},
+		{path: "sub/important.log", want: false},
+		{path: "sub/x/important.log", want: false},
+		{path: "other/important.log", want: true},
+	}
+	m := newIgnoreMatcher(root)
+	for _, tt := range tests {
+		if got := m.Ignored(tt.path, tt.isDir); got != tt.want {
+			t.Errorf("Ignored(%q, %v) = %v, want %v", tt.path, tt.isDir, got, tt.want)
+		}
+	}
+}
+
+func TestParseIgnorePattern(t *testing.T) {
+	for _, line := range []string{"", "   ", "# comment", "/", "!"} {
+		if _, ok := parseIgnorePattern(line); ok {
+			t.Errorf("parseIgnorePattern(%q) gave a pattern, want none", line)
+		}
+	}
+	p, ok := parseIgnorePattern("!logs/ ")
+	if !ok || !p.negate || !p.dirOnly || p.anchored {
+		t.Errorf(`parseIgnorePattern("!logs/ ") = %+v, %v`, p, ok)
+	}
+}
@@ -0,0 +1,116 @@
+package scan
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+type CommentRule struct {
+	Line       []string
+	BlockStart string
+	BlockEnd   string
+}
+
+var (
+	cStyle       = CommentRule{Line: []string{"//"}, BlockStart: "/*", BlockEnd: "*/"}
+	hashStyle    = CommentRule{Line: []string{"#"}}
+	dashStyle    = CommentRule{Line: []string{"--"}}
+	xmlStyle     = CommentRule{BlockStart: "<!--", BlockEnd: "-->"}
+	semiStyle    = CommentRule{Line: []string{";"}}
+	percentStyle = CommentRule{Line: []string{"%"}}
+)
+
+var extensionRules = map[string]CommentRule{
+	".go":     cStyle,
+	".c":      cStyle,
+	".h":      cStyle,
+	".cc":     cStyle,
+	".cpp":    cStyle,
+	".hpp":    cStyle,
+	".cs":     cStyle,
+	".java":   cStyle,
+	".kt":     cStyle,
+	".kts":    cStyle,
+	".scala":  cStyle,
+	".swift":  cStyle,
+	".rs":     cStyle,
+	".js":     cStyle,
+	".jsx":    cStyle,
+	".mjs":    cStyle,
+	".cjs":    cStyle,
+	".ts":     cStyle,
+	".tsx":    cStyle,
+	".dart":   cStyle,
+	".zig":    {Line: []string{"//"}},
+	".php":    {Line: []string{"//", "#"}, BlockStart: "/*", BlockEnd: "*/"},
+	".css":    {BlockStart: "/*", BlockEnd: "*/"},
+	".scss":   cStyle,
+	".less":   cStyle,
+	".proto":  cStyle,
+	".py":     {Line: []string{"#"}, BlockStart: `"""`, BlockEnd: `"""`},
+	".rb":     {Line: []string{"#"}, BlockStart: "=begin", BlockEnd: "=end"},
+	".sh":     hashStyle,
+	".bash":   hashStyle,
+	".zsh":    hashStyle,
+	".fish":   hashStyle,
+	".pl":     hashStyle,
+	".r":      hashStyle,
+	".ex":     hashStyle,
+	".exs":    hashStyle,
+	".nim":    hashStyle,
+	".tf":     {Line: []string{"#", "//"}, BlockStart: "/*", BlockEnd: "*/"},
+	".yaml":   hashStyle,
+	".yml":    hashStyle,
+	".toml":   hashStyle,
+	".ini":    {Line: []string{";", "#"}},
+	".conf":   hashStyle,
+	".sql":    {Line: []string{"--"}, BlockStart: "/*", BlockEnd: "*/"},
+	".lua":    {Line: []string{"--"}, BlockStart: "--[[", BlockEnd: "]]"},
+	".hs":     {Line: []string{"--"}, BlockStart: "{-", BlockEnd: "-}"},
+	".elm":    {Line: []string{"--"}, BlockStart: "{-", BlockEnd: "-}"},
+	".ada":    dashStyle,
+	".html":   xmlStyle,
+	".xml":    xmlStyle,
+	".vue":    {Line: []string{"//"}, BlockStart: "<!--", BlockEnd: "-->"},
+	".svelte": {Line: []string{"//"}, BlockStart: "<!--", BlockEnd: "-->"},
+	".md":     xmlStyle,
+	".lisp":   semiStyle,
+	".clj":    semiStyle,
+	".el":     semiStyle,
+	".scm":    semiStyle,
+	".asm":    semiStyle,
+	".erl":    percentStyle,
+	".tex":    percentStyle,
+	".m":      percentStyle,
+	".vim":    {Line: []string{`"`}},
+}
+
+var filenameRules = map[string]CommentRule{
+	"Makefile":       hashStyle,
+	"makefile":       hashStyle,
+	"GNUmakefile":    hashStyle,
+	"Dockerfile":     hashStyle,
+	"Containerfile":  hashStyle,
+	"Jenkinsfile":    cStyle,
+	"CMakeLists.txt": hashStyle,
+	"Rakefile":       hashStyle,
+	"Gemfile":        hashStyle,
+	"Vagrantfile":    hashStyle,
+}
+
+func RuleFor(path string) (CommentRule, bool) {
+	base := filepath.Base(path)
+	if rule, ok := filenameRules[base]; ok {
+		return rule, true
+	}
+	rule, ok := extensionRules[strings.ToLower(filepath.Ext(base))]
+	return rule, ok
+}
+
+func Register(key string, rule CommentRule) {
+	if strings.HasPrefix(key, ".") {
+		extensionRules[strings.ToLower(key)] = rule
+		return
+	}
+	filenameRules[key] = rule
+}
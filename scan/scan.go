@@ -0,0 +1,204 @@
+package scan
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const maxFileSize = 2 << 20
+
+// tagPattern matches a tag at the start of a comment's text, after any
+// further comment characters such as the extra slash of "///" or the "*" of
+// a block comment line, so "// update the TODO list" is not a task.
+var tagPattern = regexp.MustCompile(`^[\s/*!#;%-]*(TODO|FIXME|HACK)(?:\([^)]*\))?(?::|\s+|$)\s*(.*)`)
+
+type Item struct {
+	Path string
+	Line int
+	Kind string
+	Text string
+}
+
+func (i Item) Location() string {
+	return fmt.Sprintf("%s:%d", i.Path, i.Line)
+}
+
+func (i Item) Title() string {
+	if i.Text == "" {
+		return i.Kind
+	}
+	return i.Kind + ": " + i.Text
+}
+
+// Walk scans every file below root (or below the given paths, which must lie
+// inside root) that has a known comment syntax and is not git-ignored. Item
+// paths are slash-separated and relative to root.
+func Walk(root string, paths []string) ([]Item, error) {
+	if len(paths) == 0 {
+		paths = []string{root}
+	}
+	ignore := newIgnoreMatcher(root)
+	var items []Item
+	for _, p := range paths {
+		rel, err := Rel(root, p)
+		if err != nil {
+			return nil, err
+		}
+		if ignoredAncestor(ignore, rel) {
+			continue
+		}
+		err = filepath.WalkDir(filepath.Join(root, filepath.FromSlash(rel)), func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			r, err := filepath.Rel(root, path)
+			if err != nil {
+				return err
+			}
+			r = filepath.ToSlash(r)
+			if ignore.Ignored(r, d.IsDir()) {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if d.IsDir() || !d.Type().IsRegular() {
+				return nil
+			}
+			rule, ok := RuleFor(path)
+			if !ok {
+				return nil
+			}
+			found, err := scanFile(path, rule)
+			if err != nil {
+				return err
+			}
+			for _, item := range found {
+				item.Path = r
+				items = append(items, item)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.SliceStable(items, func(a, b int) bool {
+		if items[a].Path != items[b].Path {
+			return items[a].Path < items[b].Path
+		}
+		return items[a].Line < items[b].Line
+	})
+	return items, nil
+}
+
+func Rel(root, path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil {
+		return "", err
+	}
+	if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s is outside the project root %s", path, root)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+func ignoredAncestor(ignore *ignoreMatcher, rel string) bool {
+	if rel == "." {
+		return false
+	}
+	parts := strings.Split(rel, "/")
+	for i := 1; i <= len(parts); i++ {
+		isDir := i < len(parts)
+		if !isDir {
+			if st, err := os.Stat(filepath.Join(ignore.root, filepath.FromSlash(rel))); err == nil {
+				isDir = st.IsDir()
+			}
+		}
+		if ignore.Ignored(strings.Join(parts[:i], "/"), isDir) {
+			return true
+		}
+	}
+	return false
+}
+
+func scanFile(path string, rule CommentRule) ([]Item, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxFileSize || bytes.IndexByte(data[:min(len(data), 8000)], 0) >= 0 {
+		return nil, nil
+	}
+	var items []Item
+	inBlock := false
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(make([]byte, 0, 64*1024), maxFileSize)
+	for lineNo := 1; sc.Scan(); lineNo++ {
+		for _, comment := range commentsInLine(sc.Text(), rule, &inBlock) {
+			if m := tagPattern.FindStringSubmatch(comment); m != nil {
+				items = append(items, Item{Line: lineNo, Kind: m[1], Text: cleanText(m[2], rule)})
+			}
+		}
+	}
+	return items, sc.Err()
+}
+
+// commentsInLine returns the text of the comments on a line, without their
+// markers. inBlock carries an unterminated block comment over to the next
+// line.
+func commentsInLine(line string, rule CommentRule, inBlock *bool) []string {
+	var comments []string
+	for line != "" {
+		if *inBlock {
+			end := strings.Index(line, rule.BlockEnd)
+			if end < 0 {
+				comments = append(comments, line)
+				return comments
+			}
+			comments = append(comments, line[:end])
+			line = line[end+len(rule.BlockEnd):]
+			*inBlock = false
+			continue
+		}
+		lineAt, lineEnd, blockAt := -1, -1, -1
+		for _, prefix := range rule.Line {
+			if i := strings.Index(line, prefix); i >= 0 && (lineAt < 0 || i < lineAt) {
+				lineAt, lineEnd = i, i+len(prefix)
+			}
+		}
+		if rule.BlockStart != "" {
+			blockAt = strings.Index(line, rule.BlockStart)
+		}
+		switch {
+		case blockAt >= 0 && (lineAt < 0 || blockAt < lineAt):
+			line = line[blockAt+len(rule.BlockStart):]
+			*inBlock = true
+		case lineAt >= 0:
+			comments = append(comments, line[lineEnd:])
+			return comments
+		default:
+			return comments
+		}
+	}
+	return comments
+}
+
+func cleanText(text string, rule CommentRule) string {
+	text = strings.TrimSpace(text)
+	if rule.BlockEnd != "" {
+		text = strings.TrimSpace(strings.TrimSuffix(text, rule.BlockEnd))
+	}
+	return strings.TrimRight(text, " \t*")
+}
@@ -0,0 +1,105 @@
+package scan
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCommentsInLine(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    CommentRule
+		lines   []string
+		want    [][]string
+		inBlock bool
+	}{
+		{
+			name:  "line comment",
+			rule:  cStyle,
+			lines: []string{`x := 1 // TODO: fix`},
+			want:  [][]string{{" TODO: fix"}},
+		},
+		{
+			name:  "no comment",
+			rule:  cStyle,
+			lines: []string{`x := 1`},
+			want:  [][]string{nil},
+		},
+		{
+			name:  "earliest line prefix wins",
+			rule:  CommentRule{Line: []string{"//", "#"}},
+			lines: []string{`$a = 1; # note // TODO`},
+			want:  [][]string{{" note // TODO"}},
+		},
+		{
+			name:  "block comment on one line",
+			rule:  cStyle,
+			lines: []string{`f(/* TODO: a */ 1) // FIXME b`},
+			want:  [][]string{{" TODO: a ", " FIXME b"}},
+		},
+		{
+			name:  "block comment over several lines",
+			rule:  cStyle,
+			lines: []string{`/* start`, ` * TODO: middle`, ` end */ code`},
+			want:  [][]string{{" start"}, {" * TODO: middle"}, {" end "}},
+		},
+		{
+			name:    "unterminated block",
+			rule:    xmlStyle,
+			lines:   []string{`<p><!-- HACK`, `still open`},
+			want:    [][]string{{" HACK"}, {"still open"}},
+			inBlock: true,
+		},
+		{
+			name:  "line prefix inside a block comment",
+			rule:  cStyle,
+			lines: []string{`/* see http://example.com */`},
+			want:  [][]string{{" see http://example.com "}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inBlock := false
+			for i, line := range tt.lines {
+				got := commentsInLine(line, tt.rule, &inBlock)
+				if !slices.Equal(got, tt.want[i]) {
+					t.Errorf("line %d %q: got %q, want %q", i+1, line, got, tt.want[i])
+				}
+			}
+			if inBlock != tt.inBlock {
+				t.Errorf("inBlock = %v, want %v", inBlock, tt.inBlock)
+			}
+		})
+	}
+}
+
+func TestTagPattern(t *testing.T) {
+	tests := []struct {
+		comment string
+		kind    string
+		text    string
+	}{
+		{comment: " TODO: fix the parser", kind: "TODO", text: "fix the parser"},
+		{comment: " FIXME handle errors", kind: "FIXME", text: "handle errors"},
+		{comment: " HACK(alice): skip the cache", kind: "HACK", text: "skip the cache"},
+		{comment: "/ TODO doc comment", kind: "TODO", text: "doc comment"},
+		{comment: " * TODO: inside a block", kind: "TODO", text: "inside a block"},
+		{comment: "TODO", kind: "TODO", text: ""},
+		{comment: " update the TODO list"},
+		{comment: " see HACKING.md"},
+		{comment: " TODO.md lists the plans"},
+		{comment: " TODOS are tracked elsewhere"},
+		{comment: " todo: lower case"},
+	}
+	for _, tt := range tests {
+		m := tagPattern.FindStringSubmatch(tt.comment)
+		switch {
+		case m == nil && tt.kind != "":
+			t.Errorf("%q: no match, want %s %q", tt.comment, tt.kind, tt.text)
+		case m != nil && tt.kind == "":
+			t.Errorf("%q: matched %s %q, want no match", tt.comment, m[1], m[2])
+		case m != nil && (m[1] != tt.kind || m[2] != tt.text):
+			t.Errorf("%q: got %s %q, want %s %q", tt.comment, m[1], m[2], tt.kind, tt.text)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package scan
+
+import (
+	"strings"
+
+	"github.com/prime-run/togo/model"
+)
+
+type ChangeKind string
+
+const (
+	ChangeAdded     ChangeKind = "added"
+	ChangeMoved     ChangeKind = "moved"
+	ChangeCompleted ChangeKind = "completed"
+	ChangeReopened  ChangeKind = "reopened"
+)
+
+type Change struct {
+	Kind     ChangeKind
+	TodoID   int
+	Title    string
+	Location string
+	From     string
+	// Archived is set on a reopened task that must also be unarchived.
+	Archived bool
+}
+
+func Plan(todos []model.Todo, items []Item, scopes []string) []Change {
+	var candidates []model.Todo
+	for _, todo := range todos {
+		if todo.Location != "" && inScope(locationFile(todo.Location), scopes) {
+			candidates = append(candidates, todo)
+		}
+	}
+	matched := make(map[int]bool)
+	var changes []Change
+	for _, item := range items {
+		loc := item.Location()
+		todo, ok := matchCandidate(candidates, matched, item)
+		if !ok {
+			changes = append(changes, Change{Kind: ChangeAdded, Title: item.Title(), Location: loc})
+			continue
+		}
+		matched[todo.ID] = true
+		switch {
+		case todo.Completed || todo.Archived:
+			changes = append(changes, Change{Kind: ChangeReopened, TodoID: todo.ID, Title: todo.Title, Location: loc, From: todo.Location, Archived: todo.Archived})
+		case todo.Location != loc:
+			changes = append(changes, Change{Kind: ChangeMoved, TodoID: todo.ID, Title: todo.Title, Location: loc, From: todo.Location})
+		}
+	}
+	for _, todo := range candidates {
+		if !matched[todo.ID] && !todo.Completed {
+			changes = append(changes, Change{Kind: ChangeCompleted, TodoID: todo.ID, Title: todo.Title, Location: todo.Location})
+		}
+	}
+	return changes
+}
+
+// Target is the list a plan is applied to; *togo.List implements it.
+type Target interface {
+	Add(title string) (model.Todo, error)
+	SetCompleted(id int, completed bool) error
+	SetLocation(id int, location string) error
+	Unarchive(id int) error
+}
+
+func Apply(target Target, changes []Change) error {
+	for _, c := range changes {
+		var err error
+		switch c.Kind {
+		case ChangeAdded:
+			var todo model.Todo
+			if todo, err = target.Add(c.Title); err == nil {
+				err = target.SetLocation(todo.ID, c.Location)
+			}
+		case ChangeMoved:
+			err = target.SetLocation(c.TodoID, c.Location)
+		case ChangeReopened:
+			if c.Archived {
+				err = target.Unarchive(c.TodoID)
+			}
+			if err == nil {
+				err = target.SetCompleted(c.TodoID, false)
+			}
+			if err == nil {
+				err = target.SetLocation(c.TodoID, c.Location)
+			}
+		case ChangeCompleted:
+			err = target.SetCompleted(c.TodoID, true)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func matchCandidate(candidates []model.Todo, matched map[int]bool, item Item) (model.Todo, bool) {
+	var best *model.Todo
+	for i, todo := range candidates {
+		if matched[todo.ID] || locationFile(todo.Location) != item.Path || todo.Title != item.Title() {
+			continue
+		}
+		if todo.Location == item.Location() {
+			return todo, true
+		}
+		if best == nil {
+			best = &candidates[i]
+		}
+	}
+	if best == nil {
+		return model.Todo{}, false
+	}
+	return *best, true
+}
+
+func locationFile(location string) string {
+	if i := strings.LastIndex(location, ":"); i >= 0 {
+		return location[:i]
+	}
+	return location
+}
+
+func inScope(file string, scopes []string) bool {
+	if len(scopes) == 0 {
+		return true
+	}
+	for _, s := range scopes {
+		if s == "." || file == s || strings.HasPrefix(file, s+"/") {
+			return true
+		}
+	}
+	return false
+}
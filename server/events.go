@@ -0,0 +1,179 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/prime-run/togo/feed"
+	"github.com/prime-run/togo/model"
+	"github.com/prime-run/togo/pkg/togo"
+)
+
+const heartbeatInterval = 30 * time.Second
+
+// Watch polls the project and global todo files and publishes the changes
+// made to them by other processes, until ctx is cancelled.
+func (s *Server) Watch(ctx context.Context, interval time.Duration) error {
+	for _, source := range []string{togo.Project, togo.Global} {
+		s.mu.Lock()
+		c, err := s.client(source)
+		if err == nil {
+			err = s.snapshot(source, c)
+		}
+		s.mu.Unlock()
+		if err != nil {
+			return err
+		}
+		go feed.WatchFile(ctx, c.Path(), interval, func() { s.reload(source) })
+	}
+	return nil
+}
+
+// snapshot records the list of a source as it is on disk, if it has not been
+// recorded yet. The caller holds s.mu.
+func (s *Server) snapshot(source string, c *togo.Client) error {
+	if _, ok := s.snapshots[source]; ok {
+		return nil
+	}
+	tl, err := model.LoadTodoListFile(c.Path())
+	if err != nil {
+		return err
+	}
+	s.snapshots[source] = tl
+	return nil
+}
+
+func (s *Server) reload(source string) {
+	s.mu.Lock()
+	tl, err := model.LoadTodoListFile(s.clients[source].Path())
+	if err != nil {
+		s.mu.Unlock()
+		return
+	}
+	var changes []model.Change
+	if prev, ok := s.snapshots[source]; ok {
+		changes = model.DiffTodoLists(prev, tl)
+	}
+	s.snapshots[source] = tl
+	s.mu.Unlock()
+	s.hub.Publish(feed.NewEvents(source, changes)...)
+}
+
+// record publishes a change the server saved and applies it to the snapshot
+// of its source, so that reload does not report it again. The caller holds
+// s.mu.
+func (s *Server) record(ev togo.Event) {
+	s.hub.Publish(feed.Event{Type: ev.Type, Source: ev.Source, Todo: ev.Task, Time: time.Now()})
+	prev, ok := s.snapshots[ev.Source]
+	if !ok {
+		return
+	}
+	todos := slices.Clone(prev.Todos)
+	i := slices.IndexFunc(todos, func(todo model.Todo) bool { return todo.UID == ev.Task.UID })
+	switch {
+	case ev.Type == togo.Deleted:
+		if i >= 0 {
+			todos = slices.Delete(todos, i, i+1)
+		}
+	case i >= 0:
+		todos[i] = ev.Task
+	default:
+		todos = append(todos, ev.Task)
+	}
+	s.snapshots[ev.Source] = model.NewTodoListFrom(todos)
+}
+
+func (s *Server) subscribe(r *http.Request) (<-chan feed.Event, func(), string, error) {
+	filter := r.URL.Query().Get("source")
+	if filter != "" {
+		if _, err := s.source(r); err != nil {
+			return nil, nil, "", err
+		}
+	}
+	events, cancel := s.hub.Subscribe()
+	return events, cancel, filter, nil
+}
+
+func (s *Server) handleSSE(w http.ResponseWriter, r *http.Request) {
+	events, cancel, filter, err := s.subscribe(r)
+	if err != nil {
+		writeError(w, statusFor(err), err)
+		return
+	}
+	defer cancel()
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("streaming not supported"))
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+		case ev := <-events:
+			if filter != "" && ev.Source != filter {
+				continue
+			}
+			data, err := json.Marshal(ev)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", ev.Type, data)
+		}
+		flusher.Flush()
+	}
+}
+
+func (s *Server) handleWebsocket(w http.ResponseWriter, r *http.Request) {
+	events, cancel, filter, err := s.subscribe(r)
+	if err != nil {
+		writeError(w, statusFor(err), err)
+		return
+	}
+	defer cancel()
+	ws, err := upgradeWebsocket(w, r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	defer ws.Close()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ws.Done():
+			return
+		case <-heartbeat.C:
+			if err := ws.writeFrame(opPing, nil); err != nil {
+				return
+			}
+		case ev := <-events:
+			if filter != "" && ev.Source != filter {
+				continue
+			}
+			data, err := json.Marshal(ev)
+			if err != nil {
+				continue
+			}
+			if err := ws.WriteText(data); err != nil {
+				return
+			}
+		}
+	}
+}
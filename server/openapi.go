@@ -0,0 +1,163 @@
+package server
+
+const openAPISpec = `{
+  "openapi": "3.0.3",
+  "info": {
+    "title": "togo",
+    "version": "1.0.0",
+    "description": "Local HTTP API for togo todo lists. Changes run the user's lifecycle hooks: a change a hook rejects fails with 422 and is not saved, and a failing post-add or post-complete hook is reported in a Warning header of the saved change. Request bodies must be sent as application/json (415 otherwise), and requests from web pages on other origins, or for a host other than localhost when no token is set, are refused with 403."
+  },
+  "components": {
+    "securitySchemes": {
+      "bearer": {"type": "http", "scheme": "bearer"}
+    },
+    "parameters": {
+      "source": {
+        "name": "source", "in": "query", "required": false,
+        "description": "Todo source to operate on. Defaults to the source the server was started with.",
+        "schema": {"type": "string", "enum": ["project", "global"]}
+      },
+      "id": {
+        "name": "id", "in": "path", "required": true,
+        "schema": {"type": "integer"}
+      }
+    },
+    "schemas": {
+      "Todo": {
+        "type": "object",
+        "properties": {
+          "id": {"type": "integer"},
+          "uid": {"type": "string"},
+          "title": {"type": "string"},
+          "completed": {"type": "boolean"},
+          "archived": {"type": "boolean"},
+          "created_at": {"type": "string", "format": "date-time"},
+          "location": {"type": "string"},
+          "branches": {"type": "array", "items": {"type": "string"}},
+          "commits": {"type": "array", "items": {"type": "string"}}
+        }
+      },
+      "Error": {
+        "type": "object",
+        "properties": {"error": {"type": "string"}}
+      }
+    },
+    "responses": {
+      "Error": {
+        "description": "Error",
+        "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Error"}}}
+      }
+    }
+  },
+  "security": [{"bearer": []}],
+  "paths": {
+    "/todos": {
+      "get": {
+        "summary": "List todos",
+        "parameters": [
+          {"$ref": "#/components/parameters/source"},
+          {"name": "status", "in": "query", "schema": {"type": "string", "enum": ["all", "pending", "completed"]}},
+          {"name": "archived", "in": "query", "description": "Defaults to false.", "schema": {"type": "string", "enum": ["false", "true", "all"]}},
+          {"name": "q", "in": "query", "description": "Case-insensitive substring match on the title.", "schema": {"type": "string"}}
+        ],
+        "responses": {
+          "200": {"description": "Matching todos", "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/Todo"}}}}},
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      },
+      "post": {
+        "summary": "Add a todo",
+        "parameters": [{"$ref": "#/components/parameters/source"}],
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {"type": "object", "required": ["title"], "properties": {"title": {"type": "string"}}}}}
+        },
+        "responses": {
+          "201": {"description": "Created todo", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Todo"}}}},
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/todos/bulk": {
+      "post": {
+        "summary": "Apply one action to several todos",
+        "parameters": [{"$ref": "#/components/parameters/source"}],
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {
+            "type": "object", "required": ["action", "ids"],
+            "properties": {
+              "action": {"type": "string", "enum": ["complete", "uncomplete", "toggle", "archive", "unarchive", "delete"]},
+              "ids": {"type": "array", "items": {"type": "integer"}}
+            }
+          }}}
+        },
+        "responses": {
+          "200": {"description": "IDs that were updated and IDs that were not found", "content": {"application/json": {"schema": {
+            "type": "object",
+            "properties": {
+              "updated": {"type": "array", "items": {"type": "integer"}},
+              "missing": {"type": "array", "items": {"type": "integer"}}
+            }
+          }}}},
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/events": {
+      "get": {
+        "summary": "Stream todo changes as server-sent events",
+        "description": "Each event is named after the change type (added, edited, toggled, archived, unarchived, deleted) and carries a JSON object with type, source, todo and time. Changes made by other processes are included.",
+        "parameters": [{"name": "source", "in": "query", "description": "Only stream changes of this source.", "schema": {"type": "string", "enum": ["project", "global"]}}],
+        "responses": {
+          "200": {"description": "Event stream", "content": {"text/event-stream": {}}},
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/ws": {
+      "get": {
+        "summary": "Stream todo changes over a websocket",
+        "description": "Upgrades to a websocket that sends one JSON text message per change, in the same shape as /events.",
+        "parameters": [{"name": "source", "in": "query", "description": "Only stream changes of this source.", "schema": {"type": "string", "enum": ["project", "global"]}}],
+        "responses": {
+          "101": {"description": "Switching protocols"},
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/todos/{id}": {
+      "parameters": [{"$ref": "#/components/parameters/id"}, {"$ref": "#/components/parameters/source"}],
+      "get": {
+        "summary": "Get a todo",
+        "responses": {
+          "200": {"description": "The todo", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Todo"}}}},
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      },
+      "patch": {
+        "summary": "Update a todo",
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {"type": "object", "properties": {
+            "title": {"type": "string"},
+            "completed": {"type": "boolean"},
+            "archived": {"type": "boolean"}
+          }}}}
+        },
+        "responses": {
+          "200": {"description": "Updated todo", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Todo"}}}},
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      },
+      "delete": {
+        "summary": "Delete a todo",
+        "responses": {
+          "204": {"description": "Deleted"},
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    }
+  }
+}
+`
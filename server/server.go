@@ -0,0 +1,490 @@
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/prime-run/togo/feed"
+	"github.com/prime-run/togo/hooks"
+	"github.com/prime-run/togo/model"
+	"github.com/prime-run/togo/pkg/togo"
+)
+
+var (
+	errUnsupportedMediaType = errors.New("request body must be application/json")
+	errBadRequest           = errors.New("invalid request body")
+)
+
+type Server struct {
+	defaultSource string
+	token         string
+	opts          []togo.Option
+	mu            sync.Mutex
+	mux           *http.ServeMux
+	hub           *feed.Hub
+	clients       map[string]*togo.Client
+	snapshots     map[string]*model.TodoList
+}
+
+// New returns a server for the todo lists of both sources, opened with opts
+// the same way the togo command opens them.
+func New(defaultSource, token string, opts ...togo.Option) *Server {
+	s := &Server{
+		defaultSource: defaultSource,
+		token:         token,
+		opts:          opts,
+		mux:           http.NewServeMux(),
+		hub:           feed.NewHub(),
+		clients:       make(map[string]*togo.Client),
+		snapshots:     make(map[string]*model.TodoList),
+	}
+	s.mux.HandleFunc("GET /openapi.json", s.handleOpenAPI)
+	s.mux.HandleFunc("GET /todos", s.auth(s.handleList))
+	s.mux.HandleFunc("POST /todos", s.auth(s.handleCreate))
+	s.mux.HandleFunc("POST /todos/bulk", s.auth(s.handleBulk))
+	s.mux.HandleFunc("GET /todos/{id}", s.auth(s.handleGet))
+	s.mux.HandleFunc("PATCH /todos/{id}", s.auth(s.handleUpdate))
+	s.mux.HandleFunc("DELETE /todos/{id}", s.auth(s.handleDelete))
+	s.mux.HandleFunc("GET /events", s.auth(s.handleSSE))
+	s.mux.HandleFunc("GET /ws", s.auth(s.handleWebsocket))
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// auth guards every route but the API description. Browsers send requests
+// to 127.0.0.1 for any page the user visits, so requests from a page on
+// another origin are refused, and without a token so are requests for
+// another host name, which is what a DNS rebinding attack sends.
+func (s *Server) auth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := checkOrigin(r); err != nil {
+			writeError(w, http.StatusForbidden, err)
+			return
+		}
+		if s.token == "" && !IsLoopback(hostname(r.Host)) {
+			writeError(w, http.StatusForbidden, fmt.Errorf("host %q is not allowed without a token", r.Host))
+			return
+		}
+		if s.token != "" {
+			given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(given), []byte(s.token)) != 1 {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="togo"`)
+				writeError(w, http.StatusUnauthorized, errors.New("missing or invalid bearer token"))
+				return
+			}
+		}
+		next(w, r)
+	}
+}
+
+// IsLoopback reports whether host, a host name or IP address without a port,
+// only reaches this machine.
+func IsLoopback(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(strings.TrimSuffix(strings.TrimPrefix(host, "["), "]"))
+	return ip != nil && ip.IsLoopback()
+}
+
+func hostname(hostport string) string {
+	if host, _, err := net.SplitHostPort(hostport); err == nil {
+		return host
+	}
+	return hostport
+}
+
+// checkOrigin refuses requests sent by a web page that is not itself served
+// from this machine. Requests without an Origin header do not come from a
+// page.
+func checkOrigin(r *http.Request) error {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return nil
+	}
+	if u, err := url.Parse(origin); err == nil && u.Host != "" && IsLoopback(u.Hostname()) {
+		return nil
+	}
+	return fmt.Errorf("origin %q is not allowed", origin)
+}
+
+// decodeJSON reads a JSON request body into v. The body must be sent as
+// application/json: browsers send other types, such as text/plain, from any
+// page without asking the server first.
+func decodeJSON(r *http.Request, v any) error {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "application/json" {
+		return errUnsupportedMediaType
+	}
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return fmt.Errorf("%w: %v", errBadRequest, err)
+	}
+	return nil
+}
+
+func (s *Server) source(r *http.Request) (string, error) {
+	source := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("source")))
+	switch source {
+	case "":
+		return s.defaultSource, nil
+	case togo.Project, togo.Global:
+		return source, nil
+	default:
+		return "", fmt.Errorf("%w %q (must be 'project' or 'global')", togo.ErrInvalidSource, source)
+	}
+}
+
+// client returns the client of a source, opening it on first use. Changes it
+// saves are published and recorded in the snapshot of the source, which is
+// why every call that may save, reads included, must hold s.mu.
+func (s *Server) client(source string) (*togo.Client, error) {
+	if c, ok := s.clients[source]; ok {
+		return c, nil
+	}
+	c, err := togo.Open(source, s.opts...)
+	if err != nil {
+		return nil, err
+	}
+	c.Subscribe(s.record)
+	s.clients[source] = c
+	return c, nil
+}
+
+func (s *Server) view(r *http.Request) ([]togo.Task, error) {
+	source, err := s.source(r)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, err := s.client(source)
+	if err != nil {
+		return nil, err
+	}
+	return c.Tasks(r.Context())
+}
+
+func (s *Server) update(r *http.Request, fn func(*togo.List) error) error {
+	source, err := s.source(r)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, err := s.client(source)
+	if err != nil {
+		return err
+	}
+	return c.Update(r.Context(), fn)
+}
+
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request) {
+	tasks, err := s.view(r)
+	if err != nil {
+		writeError(w, statusFor(err), err)
+		return
+	}
+	filter, err := parseFilter(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	todos := []togo.Task{}
+	for _, task := range tasks {
+		if filter.match(task) {
+			todos = append(todos, task)
+		}
+	}
+	writeJSON(w, http.StatusOK, todos)
+}
+
+func (s *Server) handleGet(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	tasks, err := s.view(r)
+	if err != nil {
+		writeError(w, statusFor(err), err)
+		return
+	}
+	i := slices.IndexFunc(tasks, func(task togo.Task) bool { return task.ID == id })
+	if i < 0 {
+		writeError(w, http.StatusNotFound, fmt.Errorf("%w: id %d", togo.ErrNotFound, id))
+		return
+	}
+	writeJSON(w, http.StatusOK, tasks[i])
+}
+
+type createRequest struct {
+	Title string `json:"title"`
+}
+
+func (s *Server) handleCreate(w http.ResponseWriter, r *http.Request) {
+	var req createRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, statusFor(err), err)
+		return
+	}
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		writeError(w, http.StatusBadRequest, errors.New("title is required"))
+		return
+	}
+	var created togo.Task
+	err := s.update(r, func(l *togo.List) error {
+		var err error
+		created, err = l.Add(title)
+		return err
+	})
+	if !saved(w, err) {
+		return
+	}
+	writeJSON(w, http.StatusCreated, created)
+}
+
+type updateRequest struct {
+	Title     *string `json:"title"`
+	Completed *bool   `json:"completed"`
+	Archived  *bool   `json:"archived"`
+}
+
+func (s *Server) handleUpdate(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	var req updateRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, statusFor(err), err)
+		return
+	}
+	if req.Title != nil && strings.TrimSpace(*req.Title) == "" {
+		writeError(w, http.StatusBadRequest, errors.New("title must not be empty"))
+		return
+	}
+	var updated togo.Task
+	err = s.update(r, func(l *togo.List) error {
+		if _, ok := l.Task(id); !ok {
+			return fmt.Errorf("%w: id %d", togo.ErrNotFound, id)
+		}
+		if req.Title != nil {
+			if err := l.Edit(id, strings.TrimSpace(*req.Title)); err != nil {
+				return err
+			}
+		}
+		if req.Completed != nil {
+			if err := l.SetCompleted(id, *req.Completed); err != nil {
+				return err
+			}
+		}
+		if req.Archived != nil {
+			archive := l.Unarchive
+			if *req.Archived {
+				archive = l.Archive
+			}
+			if err := archive(id); err != nil {
+				return err
+			}
+		}
+		updated, _ = l.Task(id)
+		return nil
+	})
+	if !saved(w, err) {
+		return
+	}
+	writeJSON(w, http.StatusOK, updated)
+}
+
+func (s *Server) handleDelete(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	err = s.update(r, func(l *togo.List) error {
+		return l.Delete(id)
+	})
+	if !saved(w, err) {
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type bulkRequest struct {
+	Action string `json:"action"`
+	IDs    []int  `json:"ids"`
+}
+
+type bulkResponse struct {
+	Updated []int `json:"updated"`
+	Missing []int `json:"missing"`
+}
+
+// handleBulk applies an action to every ID. IDs that are not found are
+// reported as missing; any other error, such as a hook rejecting one of the
+// changes, saves nothing.
+func (s *Server) handleBulk(w http.ResponseWriter, r *http.Request) {
+	var req bulkRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, statusFor(err), err)
+		return
+	}
+	apply, ok := bulkActions[req.Action]
+	if !ok {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("unknown action %q", req.Action))
+		return
+	}
+	var resp bulkResponse
+	err := s.update(r, func(l *togo.List) error {
+		resp = bulkResponse{Updated: []int{}, Missing: []int{}}
+		for _, id := range req.IDs {
+			switch err := apply(l, id); {
+			case err == nil:
+				resp.Updated = append(resp.Updated, id)
+			case errors.Is(err, togo.ErrNotFound):
+				resp.Missing = append(resp.Missing, id)
+			default:
+				return err
+			}
+		}
+		return nil
+	})
+	if !saved(w, err) {
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+var bulkActions = map[string]func(*togo.List, int) error{
+	"complete":   func(l *togo.List, id int) error { return l.SetCompleted(id, true) },
+	"uncomplete": func(l *togo.List, id int) error { return l.SetCompleted(id, false) },
+	"toggle":     (*togo.List).Toggle,
+	"archive":    (*togo.List).Archive,
+	"unarchive":  (*togo.List).Unarchive,
+	"delete":     (*togo.List).Delete,
+}
+
+func (s *Server) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(openAPISpec))
+}
+
+type filter struct {
+	status   string
+	archived string
+	query    string
+}
+
+func parseFilter(r *http.Request) (filter, error) {
+	q := r.URL.Query()
+	f := filter{
+		status:   strings.ToLower(q.Get("status")),
+		archived: strings.ToLower(q.Get("archived")),
+		query:    strings.ToLower(q.Get("q")),
+	}
+	switch f.status {
+	case "", "all", "pending", "completed":
+	default:
+		return f, fmt.Errorf("invalid status %q (must be 'pending', 'completed' or 'all')", f.status)
+	}
+	switch f.archived {
+	case "", "false", "true", "all":
+	default:
+		return f, fmt.Errorf("invalid archived %q (must be 'true', 'false' or 'all')", f.archived)
+	}
+	return f, nil
+}
+
+func (f filter) match(todo togo.Task) bool {
+	switch f.status {
+	case "pending":
+		if todo.Completed {
+			return false
+		}
+	case "completed":
+		if !todo.Completed {
+			return false
+		}
+	}
+	switch f.archived {
+	case "", "false":
+		if todo.Archived {
+			return false
+		}
+	case "true":
+		if !todo.Archived {
+			return false
+		}
+	}
+	return f.query == "" || strings.Contains(strings.ToLower(todo.Title), f.query)
+}
+
+func pathID(r *http.Request) (int, error) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		return 0, fmt.Errorf("invalid todo id %q", r.PathValue("id"))
+	}
+	return id, nil
+}
+
+// saved writes the error response for an update that failed and reports
+// whether the change was saved. When only post-add or post-complete hooks
+// failed, the change was saved and their error goes in a Warning header.
+func saved(w http.ResponseWriter, err error) bool {
+	var postErr *togo.PostHookError
+	switch {
+	case err == nil:
+		return true
+	case errors.As(err, &postErr):
+		w.Header().Set("Warning", "199 togo "+strconv.Quote(postErr.Err.Error()))
+		return true
+	default:
+		writeError(w, statusFor(err), err)
+		return false
+	}
+}
+
+func statusFor(err error) int {
+	var newer model.NewerSchemaError
+	var hookErr *hooks.Error
+	switch {
+	case errors.Is(err, togo.ErrNotFound):
+		return http.StatusNotFound
+	case errors.As(err, &newer):
+		return http.StatusConflict
+	case errors.As(err, &hookErr):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, togo.ErrInvalidSource), errors.Is(err, errBadRequest):
+		return http.StatusBadRequest
+	case errors.Is(err, errUnsupportedMediaType):
+		return http.StatusUnsupportedMediaType
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,92 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/prime-run/togo/hooks"
+	"github.com/prime-run/togo/model"
+	"github.com/prime-run/togo/pkg/togo"
+)
+
+// postHook is an after-hook waiting for its change to be saved.
+type postHook struct {
+	event hooks.Event
+	uid   string
+}
+
+// applyWithHooks runs the hooks for a before-event on the task and then op,
+// reporting a rejection in the status bar.
+func (m *TodoTableModel) applyWithHooks(event hooks.Event, id int, op func(int) bool) bool {
+	if err := m.hooks.Mutate(context.Background(), m.todoList, event, id, op); err != nil {
+		m.SetStatusMessage("Aborted by hook: " + err.Error())
+		return false
+	}
+	return true
+}
+
+// queuePostHooks schedules the after-hooks for event on the tasks with the
+// given IDs. The table only writes to disk when it saves, so they run then,
+// once the change can be seen in the todo file, and never for a change that
+// is not saved.
+func (m *TodoTableModel) queuePostHooks(event hooks.Event, ids ...int) {
+	for _, id := range ids {
+		todo := m.todoList.GetTodoByID(id)
+		if todo == nil {
+			continue
+		}
+		hook := postHook{event: event, uid: todo.UID}
+		if !slices.Contains(m.postHooks, hook) {
+			m.postHooks = append(m.postHooks, hook)
+		}
+	}
+}
+
+// runPostHooks runs the queued after-hooks with the tasks as they were saved,
+// skipping tasks that were deleted or reopened in the meantime.
+func (m *TodoTableModel) runPostHooks(ctx context.Context) error {
+	queued := m.postHooks
+	m.postHooks = nil
+	var errs []error
+	for _, hook := range queued {
+		i := slices.IndexFunc(m.todoList.Todos, func(todo model.Todo) bool { return todo.UID == hook.uid })
+		if i < 0 || (hook.event == hooks.PostComplete && !m.todoList.Todos[i].Completed) {
+			continue
+		}
+		if _, err := m.hooks.Run(ctx, hook.event, m.todoList.Todos[i]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return &togo.PostHookError{Err: errors.Join(errs...)}
+	}
+	return nil
+}
+
+// saveBeforeSwitch saves the list before the table switches to another
+// source. It reports a failed save in the status bar and returns false;
+// failing after-hooks do not stop the switch and are returned as a warning
+// to show with its result.
+func (m *TodoTableModel) saveBeforeSwitch(ctx context.Context) (string, bool) {
+	err := m.Save(ctx)
+	var postErr *togo.PostHookError
+	switch {
+	case err == nil:
+		return "", true
+	case errors.As(err, &postErr):
+		return "; hook failed: " + postErr.Err.Error(), true
+	default:
+		m.SetStatusMessage("save failed: " + err.Error())
+		return "", false
+	}
+}
+
+// toggleWithHooks flips the task's completion, running the pre-complete hooks
+// when it is being completed.
+func (m *TodoTableModel) toggleWithHooks(todo model.Todo) bool {
+	if todo.Completed {
+		return m.todoList.Toggle(todo.ID)
+	}
+	return m.applyWithHooks(hooks.PreComplete, todo.ID, m.todoList.Toggle)
+}
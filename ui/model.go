@@ -1,9 +1,17 @@
 package ui
 
 import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
 	"github.com/charmbracelet/bubbles/table"
 	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/prime-run/togo/feed"
+	"github.com/prime-run/togo/hooks"
 	"github.com/prime-run/togo/model"
+	"github.com/prime-run/togo/pkg/togo"
 )
 
 type Mode int
@@ -15,6 +23,10 @@ const (
 	ModeArchiveConfirm
 	ModeAddTask
 	ModeEditTask
+	ModePomodoro
+	ModeEstimate
+	ModeStats
+	ModeSnooze
 )
 
 type TodoTableModel struct {
@@ -33,26 +45,66 @@ type TodoTableModel struct {
 	showArchived     bool
 	showAll          bool
 	showArchivedOnly bool
+	showSnoozedOnly  bool
+	activeList       string
+	focus            bool
 	statusMessage    string
 	showHelp         bool
 	sourceLabel      string
 	todoFileName     string
+	clientOptions    []togo.Option
 	projectName      string
+	store            togo.Store
+	fileStamps       []feed.Stamp
+	baseList         *model.TodoList
+	watchGeneration  int
+	hooks            *hooks.Runner
+	postHooks        []postHook
+	pomodoro         pomodoroSession
+	timeStyle        model.TimeStyle
 }
 
 func (m TodoTableModel) GetSourceLabel() string {
 	return m.sourceLabel
 }
 
-func (m TodoTableModel) GetTodoList() *model.TodoList {
-	return m.todoList
+// Save writes the in-memory list back through the store, merging in
+// whatever other processes saved since it was loaded, and then runs the
+// after-hooks of the saved changes. When only those fail, the list was saved
+// and the error is a *togo.PostHookError.
+func (m *TodoTableModel) Save(ctx context.Context) error {
+	if m.store == nil {
+		return nil
+	}
+	saved, err := m.store.Sync(ctx, m.baseList.Todos, m.todoList.Todos)
+	if err != nil {
+		return err
+	}
+	m.todoList = model.NewTodoListFrom(saved)
+	m.watchSource()
+	return m.runPostHooks(ctx)
+}
+
+// SetClientOptions sets the options the table opens other sources with, so
+// they are opened the way the command that started it opens them.
+func (m *TodoTableModel) SetClientOptions(opts ...togo.Option) {
+	m.clientOptions = opts
+}
+
+// openSource opens another source with the table's client options. The table
+// owns the terminal, so the output of hooks is folded into their errors,
+// which the status bar shows.
+func (m TodoTableModel) openSource(source string) (*togo.Client, error) {
+	opts := append([]togo.Option{togo.WithFileName(m.todoFileName)}, m.clientOptions...)
+	return togo.Open(source, append(opts, togo.WithHookOutput(nil))...)
 }
 
-func (m *TodoTableModel) SetSource(label, filename string) {
-	m.sourceLabel = label
-	m.todoFileName = filename
+func (m *TodoTableModel) setStore(store togo.Store) {
+	m.store = store
+	m.sourceLabel = store.Source()
+	m.todoFileName = filepath.Base(store.Paths()[0])
 
-	if label == "project" {
+	if m.sourceLabel == togo.Project {
 		if projectName, hasProject := model.GetProjectRootName(); hasProject {
 			m.projectName = projectName
 		} else {
@@ -61,4 +113,33 @@ func (m *TodoTableModel) SetSource(label, filename string) {
 	} else {
 		m.projectName = ""
 	}
+	m.watchSource()
+}
+
+func (m TodoTableModel) isAggregate() bool {
+	_, ok := m.store.(*togo.Multi)
+	return ok
+}
+
+// sourceName labels the file a task comes from when several are shown.
+// Tasks that were not saved yet are labelled with the file they will go to.
+func (m TodoTableModel) sourceName(todo model.Todo) string {
+	multi, ok := m.store.(*togo.Multi)
+	if !ok {
+		return m.sourceLabel
+	}
+	client, ok := multi.Origin(todo)
+	if !ok {
+		client = multi.Clients()[0]
+	}
+	if client.Source() == togo.Global {
+		return togo.Global
+	}
+	dir := filepath.Dir(client.Path())
+	if cwd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(cwd, dir); err == nil && rel != "." && !strings.HasPrefix(rel, "..") {
+			return rel
+		}
+	}
+	return filepath.Base(dir)
 }
@@ -0,0 +1,236 @@
+package ui
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/prime-run/togo/config"
+	"github.com/prime-run/togo/hooks"
+	"github.com/prime-run/togo/report"
+)
+
+type pomodoroPhase int
+
+const (
+	phaseWork pomodoroPhase = iota
+	phaseShortBreak
+	phaseLongBreak
+)
+
+func (p pomodoroPhase) String() string {
+	switch p {
+	case phaseShortBreak:
+		return "short_break"
+	case phaseLongBreak:
+		return "long_break"
+	}
+	return "work"
+}
+
+func (p pomodoroPhase) label() string {
+	switch p {
+	case phaseShortBreak:
+		return "Short break"
+	case phaseLongBreak:
+		return "Long break"
+	}
+	return "Focus"
+}
+
+// pomodoroSession is a run of work and break phases on one task.
+type pomodoroSession struct {
+	cfg     config.Pomodoro
+	uid     string
+	title   string
+	phase   pomodoroPhase
+	started time.Time
+	ends    time.Time
+	paused  bool
+	left    time.Duration // time left in the phase while paused
+	done    int           // work phases finished in this session
+}
+
+type pomodoroCommandMsg struct {
+	err error
+}
+
+func (s pomodoroSession) length() time.Duration {
+	switch s.phase {
+	case phaseShortBreak:
+		return time.Duration(s.cfg.ShortBreak)
+	case phaseLongBreak:
+		return time.Duration(s.cfg.LongBreak)
+	}
+	return time.Duration(s.cfg.Work)
+}
+
+func (s pomodoroSession) remaining(now time.Time) time.Duration {
+	if s.paused {
+		return s.left
+	}
+	return max(s.ends.Sub(now), 0)
+}
+
+// startPomodoro starts a work phase on the task under the cursor.
+func (m *TodoTableModel) startPomodoro() tea.Cmd {
+	visible := m.visibleTodos()
+	if m.table.Cursor() >= len(visible) {
+		return nil
+	}
+	todo := visible[m.table.Cursor()]
+	if todo.Completed || todo.Archived {
+		m.SetStatusMessage("Pomodoros can only run on pending tasks")
+		return nil
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		m.SetStatusMessage("config: " + err.Error())
+		return nil
+	}
+	now := time.Now()
+	m.pomodoro = pomodoroSession{
+		cfg:     cfg.Pomodoro,
+		uid:     todo.UID,
+		title:   todo.Title,
+		phase:   phaseWork,
+		started: now,
+		ends:    now.Add(time.Duration(cfg.Pomodoro.Work)),
+	}
+	m.mode = ModePomodoro
+	return nil
+}
+
+// togglePomodoroPause pauses or resumes the countdown of the current phase.
+func (m *TodoTableModel) togglePomodoroPause() {
+	now := time.Now()
+	if m.pomodoro.paused {
+		m.pomodoro.ends = now.Add(m.pomodoro.left)
+	} else {
+		m.pomodoro.left = m.pomodoro.remaining(now)
+	}
+	m.pomodoro.paused = !m.pomodoro.paused
+}
+
+// advancePomodoro moves to the next phase once the current one is over, or
+// right away when skip is set. Finishing a work phase logs a pomodoro on the
+// task; a skipped work phase is not logged.
+func (m *TodoTableModel) advancePomodoro(now time.Time, skip bool) tea.Cmd {
+	s := &m.pomodoro
+	if !skip && (s.paused || now.Before(s.ends)) {
+		return nil
+	}
+	if s.phase == phaseWork {
+		s.phase = phaseShortBreak
+		if !skip {
+			for _, todo := range m.todoList.Todos {
+				if todo.UID == s.uid {
+					m.todoList.LogPomodoro(todo.ID, s.started, s.ends)
+					break
+				}
+			}
+			if s.done++; s.done%s.cfg.LongBreakEvery == 0 {
+				s.phase = phaseLongBreak
+			}
+		}
+	} else {
+		s.phase = phaseWork
+	}
+	s.started, s.paused = now, false
+	s.ends = now.Add(s.length())
+	return m.pomodoroNotifyCmd()
+}
+
+// stopPomodoro ends the session, dropping the unfinished phase.
+func (m *TodoTableModel) stopPomodoro() {
+	if m.pomodoro.done == 1 {
+		m.SetStatusMessage("1 pomodoro finished")
+	} else if m.pomodoro.done > 1 {
+		m.SetStatusMessage(fmt.Sprintf("%d pomodoros finished", m.pomodoro.done))
+	}
+	m.pomodoro = pomodoroSession{}
+	m.mode = ModeNormal
+	m.updateRows()
+}
+
+// pomodoroNotifyCmd announces a phase change with the configured command,
+// or the terminal bell when there is none.
+func (m TodoTableModel) pomodoroNotifyCmd() tea.Cmd {
+	s := m.pomodoro
+	if s.cfg.Command == "" {
+		return func() tea.Msg {
+			_, _ = os.Stdout.WriteString("\a")
+			return nil
+		}
+	}
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), hooks.Timeout)
+		defer cancel()
+		var cmd *exec.Cmd
+		if runtime.GOOS == "windows" {
+			cmd = exec.CommandContext(ctx, "cmd", "/C", s.cfg.Command)
+		} else {
+			cmd = exec.CommandContext(ctx, "sh", "-c", s.cfg.Command)
+		}
+		cmd.Env = append(os.Environ(),
+			"TOGO_POMODORO_PHASE="+s.phase.String(),
+			"TOGO_POMODORO_COUNT="+strconv.Itoa(s.done),
+			"TOGO_TASK_UID="+s.uid,
+			"TOGO_TASK_TITLE="+s.title,
+		)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				lines := strings.Split(msg, "\n")
+				err = fmt.Errorf("%w: %s", err, lines[len(lines)-1])
+			}
+			return pomodoroCommandMsg{err: err}
+		}
+		return nil
+	}
+}
+
+func (m TodoTableModel) pomodoroView() string {
+	s := m.pomodoro
+	now := time.Now()
+	left := s.remaining(now).Round(time.Second)
+	clock := fmt.Sprintf("%02d:%02d", int(left.Minutes()), int(left.Seconds())%60)
+	if s.paused {
+		clock += "  (paused)"
+	}
+
+	const barWidth = 40
+	elapsed := s.length() - left
+	filled := min(int(elapsed*barWidth/max(s.length(), 1)), barWidth)
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", barWidth-filled)
+
+	style := statusPendingStyle
+	if s.phase != phaseWork {
+		style = statusCompleteStyle
+	}
+	view := taskTitleStyle.Render(s.title) + "\n" +
+		style.Render(s.phase.label()) + "  " + pomodoroClockStyle.Render(clock) + "\n" +
+		style.Render(bar) + "\n\n" +
+		fmt.Sprintf("Finished this session: %d  |  long break every %d", s.done, s.cfg.LongBreakEvery) + "\n"
+
+	if today := report.SummarizePomodoros(m.todoList.Todos, report.StartOfDay(now), now.Add(time.Second)); len(today) > 0 {
+		view += "\nToday:\n"
+		for i, t := range today {
+			if i == 5 {
+				view += createdAtStyle.Render(fmt.Sprintf("  … %d more", len(today)-i)) + "\n"
+				break
+			}
+			view += createdAtStyle.Render(fmt.Sprintf("  %2d  %s", t.Count, t.Todo.Title)) + "\n"
+		}
+	}
+	view += "\n" + helpStyle.Render("space: pause/resume  n: next phase  esc: stop")
+	return fullScreenStyle.Width(m.width).Height(m.height).Render(fullTaskViewStyle.Render(view))
+}
@@ -0,0 +1,85 @@
+package ui
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/prime-run/togo/feed"
+	"github.com/prime-run/togo/model"
+)
+
+const reloadPollInterval = 500 * time.Millisecond
+
+type todoFileChangedMsg struct {
+	generation int
+	stamps     []feed.Stamp
+}
+
+func (m TodoTableModel) watchTodoFileCmd() tea.Cmd {
+	if m.store == nil {
+		return nil
+	}
+	paths, stamps, generation := m.store.Paths(), m.fileStamps, m.watchGeneration
+	return func() tea.Msg {
+		stamps = feed.WaitForAnyChange(context.Background(), paths, stamps, reloadPollInterval)
+		return todoFileChangedMsg{generation: generation, stamps: stamps}
+	}
+}
+
+func (m *TodoTableModel) watchSource() {
+	m.baseList = m.todoList.Clone()
+	m.watchGeneration++
+	if m.store == nil {
+		return
+	}
+	paths := m.store.Paths()
+	m.fileStamps = make([]feed.Stamp, len(paths))
+	for i, path := range paths {
+		m.fileStamps[i] = feed.StatFile(path)
+	}
+	if tasks, err := m.store.Tasks(context.Background()); err == nil {
+		m.baseList = model.NewTodoListFrom(tasks)
+	}
+}
+
+// reloadFromDisk merges changes other processes made to the active source
+// into the in-memory list, keeping local edits, and returns how many
+// external changes were found. Tasks added locally make way for the IDs of
+// tasks added on disk, as Client.Sync does when the list is saved.
+func (m *TodoTableModel) reloadFromDisk() (int, error) {
+	if m.store == nil || m.baseList == nil {
+		return 0, nil
+	}
+	tasks, err := m.store.Tasks(context.Background())
+	if err != nil {
+		return 0, err
+	}
+	disk := model.NewTodoListFrom(tasks)
+	changes := model.DiffTodoLists(m.baseList, disk)
+	if len(changes) == 0 {
+		return 0, nil
+	}
+	selected := make(map[string]bool, len(m.selectedTodoIDs))
+	for id := range m.selectedTodoIDs {
+		if todo := m.todoList.GetTodoByID(id); todo != nil {
+			selected[todo.UID] = true
+		}
+	}
+	ours := model.RenumberAdded(m.baseList, m.todoList, disk)
+	merged, err := model.MergeTodoLists(m.baseList, ours, disk)
+	if err != nil {
+		return 0, err
+	}
+	m.baseList = disk
+	m.todoList = merged
+	m.selectedTodoIDs = make(map[int]bool, len(selected))
+	for _, todo := range merged.Todos {
+		if selected[todo.UID] {
+			m.selectedTodoIDs[todo.ID] = true
+		}
+	}
+	m.bulkActionActive = len(m.selectedTodoIDs) > 0
+	m.updateRows()
+	return len(changes), nil
+}
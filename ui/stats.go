@@ -0,0 +1,173 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/prime-run/togo/model"
+	"github.com/prime-run/togo/report"
+)
+
+// StatsWeeks is how many weeks of history the stats show by default.
+const StatsWeeks = 12
+
+var (
+	statsHeadingStyle = lipgloss.NewStyle().
+				Bold(true).
+				Foreground(lipgloss.Color("#00D3EE"))
+	statsBarStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("28"))
+	heatmapLevels = []lipgloss.Style{
+		lipgloss.NewStyle().Foreground(lipgloss.Color("237")),
+		lipgloss.NewStyle().Foreground(lipgloss.Color("#0e4429")),
+		lipgloss.NewStyle().Foreground(lipgloss.Color("#006d32")),
+		lipgloss.NewStyle().Foreground(lipgloss.Color("#26a641")),
+		lipgloss.NewStyle().Foreground(lipgloss.Color("#39d353")),
+	}
+)
+
+// RenderStats lays out stats in two columns when width allows, and below
+// each other otherwise.
+func RenderStats(s report.Stats, now time.Time, width int) string {
+	left := lipgloss.JoinVertical(lipgloss.Left, statsSummary(s), "", statsWeeks(s))
+	right := lipgloss.JoinVertical(lipgloss.Left, statsHeatmap(s), "", statsTags(s), "", statsOldest(s, now))
+	if lipgloss.Width(left)+lipgloss.Width(right)+4 <= width {
+		return lipgloss.JoinHorizontal(lipgloss.Top, left, "    ", right)
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, left, "", right)
+}
+
+func statsSummary(s report.Stats) string {
+	lines := []string{
+		statsHeadingStyle.Render("Tasks"),
+		fmt.Sprintf("%s open  %s done  %d archived  (%.0f%% done)",
+			statusPendingStyle.Render(fmt.Sprint(s.Open)), statusCompleteStyle.Render(fmt.Sprint(s.Done)),
+			s.Archived, s.CompletionRate*100),
+	}
+	if s.AvgTimeToComplete > 0 {
+		lines = append(lines, fmt.Sprintf("Time to complete: %s on average, median %s",
+			model.HumanizeDuration(s.AvgTimeToComplete), model.HumanizeDuration(s.MedianTimeToComplete)))
+	}
+	lines = append(lines, fmt.Sprintf("Streak: %s (longest %s)", dayCount(s.CurrentStreak), dayCount(s.LongestStreak)))
+	return strings.Join(lines, "\n")
+}
+
+func statsWeeks(s report.Stats) string {
+	const barWidth = 24
+	top := 1
+	for _, w := range s.Weeks {
+		top = max(top, w.Completed)
+	}
+	lines := []string{statsHeadingStyle.Render("Completed per week")}
+	for _, w := range s.Weeks {
+		bar := strings.Repeat("█", w.Completed*barWidth/top)
+		if bar == "" && w.Completed > 0 {
+			bar = "▏"
+		}
+		pad := strings.Repeat(" ", barWidth-len([]rune(bar)))
+		lines = append(lines, fmt.Sprintf("%s  %s%s %3d  %s", w.Start.Format("Jan 02"), statsBarStyle.Render(bar), pad,
+			w.Completed, createdAtStyle.Render(fmt.Sprintf("+%d new", w.Created))))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// statsHeatmap draws a calendar of completions, one column per week and one
+// row per weekday.
+func statsHeatmap(s report.Stats) string {
+	if len(s.Days) == 0 {
+		return ""
+	}
+	weeks := (len(s.Days) + 6) / 7
+	months := []byte(strings.Repeat(" ", 4+2*weeks))
+	lastMonth := time.Month(0)
+	for w := 0; w < weeks; w++ {
+		if m := s.Days[w*7].Date.Month(); m != lastMonth {
+			if 4+2*w+3 <= len(months) && (w == 0 || months[4+2*w-1] == ' ') {
+				copy(months[4+2*w:], m.String()[:3])
+			}
+			lastMonth = m
+		}
+	}
+	lines := []string{statsHeadingStyle.Render("Completions"), strings.TrimRight(string(months), " ")}
+	for weekday := 0; weekday < 7; weekday++ {
+		var b strings.Builder
+		label := []string{"Mon", "", "Wed", "", "Fri", "", "Sun"}[weekday]
+		fmt.Fprintf(&b, "%-3s ", label)
+		for w := 0; w < weeks; w++ {
+			i := w*7 + weekday
+			if i >= len(s.Days) {
+				break
+			}
+			b.WriteString(heatmapCell(s.Days[i].Completed) + " ")
+		}
+		lines = append(lines, strings.TrimRight(b.String(), " "))
+	}
+	legend := "Less "
+	for _, n := range []int{0, 1, 2, 4, 6} {
+		legend += heatmapCell(n) + " "
+	}
+	return strings.Join(append(lines, createdAtStyle.Render(legend+"More")), "\n")
+}
+
+func heatmapCell(completed int) string {
+	level := 0
+	switch {
+	case completed >= 6:
+		level = 4
+	case completed >= 4:
+		level = 3
+	case completed >= 2:
+		level = 2
+	case completed == 1:
+		level = 1
+	}
+	return heatmapLevels[level].Render("■")
+}
+
+func statsTags(s report.Stats) string {
+	lines := []string{statsHeadingStyle.Render("Tags")}
+	if len(s.Tags) == 0 {
+		return strings.Join(append(lines, createdAtStyle.Render("No #tags in task titles")), "\n")
+	}
+	width := 0
+	for _, t := range s.Tags[:min(len(s.Tags), 5)] {
+		width = max(width, len(t.Tag)+1)
+	}
+	for _, t := range s.Tags[:min(len(s.Tags), 5)] {
+		lines = append(lines, fmt.Sprintf("%-*s  %d open  %d done", width, "#"+t.Tag, t.Open, t.Done))
+	}
+	if len(s.Tags) > 5 {
+		lines = append(lines, createdAtStyle.Render(fmt.Sprintf("… %d more", len(s.Tags)-5)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func statsOldest(s report.Stats, now time.Time) string {
+	lines := []string{statsHeadingStyle.Render("Oldest open tasks")}
+	if len(s.Oldest) == 0 {
+		return strings.Join(append(lines, createdAtStyle.Render("Nothing open")), "\n")
+	}
+	ages := make([]string, len(s.Oldest))
+	width := 0
+	for i, todo := range s.Oldest {
+		ages[i] = model.HumanizeDuration(now.Sub(todo.CreatedAt))
+		width = max(width, len(ages[i]))
+	}
+	for i, todo := range s.Oldest {
+		title := []rune(todo.Title)
+		if len(title) > 32 {
+			title = append(title[:31], '…')
+		}
+		lines = append(lines, fmt.Sprintf("%-*s  %s", width, ages[i], string(title)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func dayCount(n int) string {
+	if n == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", n)
+}
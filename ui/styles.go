@@ -20,6 +20,13 @@ var (
 				Foreground(lipgloss.Color("28"))
 	statusPendingStyle = lipgloss.NewStyle().
 				Foreground(lipgloss.Color("136"))
+	statusTrackingStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#00D3EE"))
+	statusSnoozedStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("245"))
+	pomodoroClockStyle = lipgloss.NewStyle().
+				Bold(true).
+				Foreground(lipgloss.Color("252"))
 	helpStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#00D3EE"))
 	confirmStyle = lipgloss.NewStyle().
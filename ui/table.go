@@ -1,11 +1,19 @@
 package ui
 
 import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
 	"github.com/charmbracelet/bubbles/table"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/prime-run/togo/config"
+	"github.com/prime-run/togo/hooks"
 	"github.com/prime-run/togo/model"
+	"github.com/prime-run/togo/pkg/togo"
 )
 
 const (
@@ -13,7 +21,8 @@ const (
 	checkboxFilled = " \u2611 "
 )
 
-func NewTodoTable(todoList *model.TodoList) TodoTableModel {
+func NewTodoTable(store togo.Store, tasks []togo.Task) TodoTableModel {
+	todoList := model.NewTodoListFrom(tasks)
 	displayWidth := 80
 	checkboxColWidth := 3
 	statusColWidth := 15
@@ -70,8 +79,15 @@ func NewTodoTable(todoList *model.TodoList) TodoTableModel {
 		showAll:          true,
 		showArchivedOnly: false,
 		statusMessage:    "",
-		showHelp:         true,
+		showHelp:         false,
+		hooks:            hooks.New(nil),
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		m.statusMessage = "config: " + err.Error()
 	}
+	m.timeStyle = model.TimeStyle{Absolute: cfg.TimeFormat.Absolute, Layout: cfg.TimeFormat.Layout}
+	m.setStore(store)
 	m.updateRows()
 	return m
 }
@@ -79,18 +95,174 @@ func NewTodoTable(todoList *model.TodoList) TodoTableModel {
 func (m *TodoTableModel) SetShowArchivedOnly(show bool) {
 	m.showArchivedOnly = show
 	m.showAll = false
+	m.showSnoozedOnly = false
 	m.updateRows()
 }
 
 func (m *TodoTableModel) SetShowAll(show bool) {
 	m.showAll = show
 	m.showArchivedOnly = false
+	m.showSnoozedOnly = false
 	m.updateRows()
 }
 
 func (m *TodoTableModel) SetShowActiveOnly(show bool) {
 	m.showAll = false
 	m.showArchivedOnly = false
+	m.showSnoozedOnly = false
+	m.updateRows()
+}
+
+// SetShowSnoozedOnly shows the snoozed tasks, the ones coming back first at
+// the top.
+func (m *TodoTableModel) SetShowSnoozedOnly(show bool) {
+	m.showSnoozedOnly = show
+	m.showAll = false
+	m.showArchivedOnly = false
+	m.updateRows()
+}
+
+// SetList limits the table to one named list; "" shows every list.
+func (m *TodoTableModel) SetList(name string) {
+	if name = strings.TrimSpace(name); name != "" {
+		name = model.Todo{List: model.NormalizeListName(name)}.ListName()
+	}
+	m.activeList = name
+	m.updateRows()
+}
+
+// nextList cycles the list filter through all lists, the default list and
+// then the named lists in order.
+func (m *TodoTableModel) nextList() {
+	names := append([]string{""}, model.ListNames(m.todoList.Todos)...)
+	next := 0
+	if i := slices.Index(names, m.activeList); i >= 0 {
+		next = (i + 1) % len(names)
+	}
+	m.SetList(names[next])
+}
+
+// visibleTodos returns the todos the table shows, in row order.
+func (m TodoTableModel) visibleTodos() []model.Todo {
+	var todos []model.Todo
+	if m.showAll {
+		todos = m.todoList.Todos
+	} else if m.showArchivedOnly {
+		todos = m.todoList.GetArchivedTodos()
+	} else if m.showSnoozedOnly {
+		todos = m.todoList.GetSnoozedTodos()
+	} else {
+		todos = m.todoList.GetActiveTodos()
+	}
+	if m.focus {
+		var planned []model.Todo
+		now := time.Now()
+		for _, todo := range m.todoList.Todos {
+			if todo.PlannedToday(now) && !todo.Archived {
+				planned = append(planned, todo)
+			}
+		}
+		todos = planned
+	}
+	if m.activeList == "" {
+		return todos
+	}
+	var inList []model.Todo
+	for _, todo := range todos {
+		if todo.InList(m.activeList) {
+			inList = append(inList, todo)
+		}
+	}
+	return inList
+}
+
+// startSnooze asks until when to snooze the selected tasks, or the one under
+// the cursor.
+func (m *TodoTableModel) startSnooze() tea.Cmd {
+	m.editTaskID = 0
+	if !m.bulkActionActive || len(m.selectedTodoIDs) == 0 {
+		visible := m.visibleTodos()
+		if m.table.Cursor() >= len(visible) {
+			return nil
+		}
+		m.editTaskID = visible[m.table.Cursor()].ID
+	}
+	m.textInput.Reset()
+	m.textInput.Placeholder = "tomorrow, mon, 2w or 2006-01-02 (none brings it back)"
+	m.textInput.Focus()
+	m.mode = ModeSnooze
+	return textinput.Blink
+}
+
+// snooze hides the tasks chosen by startSnooze until the time in the text
+// input.
+func (m *TodoTableModel) snooze() {
+	now := time.Now()
+	until, err := model.ParseWhen(m.textInput.Value(), now)
+	if err == nil && !until.IsZero() && !until.After(now) {
+		err = fmt.Errorf("%s is not in the future", m.timeStyle.Format(until, now))
+	}
+	if err != nil {
+		m.SetStatusMessage(err.Error())
+		return
+	}
+	ids := []int{m.editTaskID}
+	if m.editTaskID == 0 {
+		ids = ids[:0]
+		for id := range m.selectedTodoIDs {
+			ids = append(ids, id)
+		}
+		m.selectedTodoIDs = make(map[int]bool)
+		m.bulkActionActive = false
+	}
+	for _, id := range ids {
+		m.todoList.Snooze(id, until)
+	}
+	subject := "Task"
+	if len(ids) > 1 {
+		subject = fmt.Sprintf("%d tasks", len(ids))
+	}
+	if until.IsZero() {
+		m.SetStatusMessage(subject + " back in the active list")
+	} else {
+		m.SetStatusMessage(subject + " snoozed until " + m.timeStyle.Format(until, now))
+	}
+}
+
+// togglePlanned puts the selected tasks, or the one under the cursor, on
+// today's plan, or takes them off if they are all on it already.
+func (m *TodoTableModel) togglePlanned() {
+	var ids []int
+	if len(m.selectedTodoIDs) > 0 && m.bulkActionActive {
+		for id := range m.selectedTodoIDs {
+			ids = append(ids, id)
+		}
+	} else if visible := m.visibleTodos(); m.table.Cursor() < len(visible) {
+		ids = append(ids, visible[m.table.Cursor()].ID)
+	}
+	if len(ids) == 0 {
+		return
+	}
+	now := time.Now()
+	day := time.Time{}
+	for _, id := range ids {
+		if todo := m.findTodoByID(id); todo != nil && !todo.PlannedToday(now) {
+			day = now
+		}
+	}
+	for _, id := range ids {
+		m.todoList.PlanFor(id, day)
+	}
+	switch {
+	case day.IsZero() && len(ids) == 1:
+		m.SetStatusMessage("Taken off today's plan")
+	case day.IsZero():
+		m.SetStatusMessage(fmt.Sprintf("%d tasks taken off today's plan", len(ids)))
+	case len(ids) == 1:
+		m.SetStatusMessage("Planned for today")
+	default:
+		m.SetStatusMessage(fmt.Sprintf("%d tasks planned for today", len(ids)))
+	}
 	m.updateRows()
 }
 
@@ -100,31 +272,37 @@ func (m *TodoTableModel) updateRows() {
 		availableWidth = 40
 	}
 
+	now := time.Now()
 	checkboxColWidth := 5
 	statusColWidth := 15
 	createdAtColWidth := 15
-	titleColWidth := availableWidth - checkboxColWidth - statusColWidth - createdAtColWidth - 6
+	if m.timeStyle.Absolute {
+		createdAtColWidth = max(createdAtColWidth, len(m.timeStyle.Format(now, now))+1)
+	}
+	sourceColWidth := 0
+	if m.isAggregate() {
+		sourceColWidth = 15
+	}
+	titleColWidth := availableWidth - checkboxColWidth - statusColWidth - createdAtColWidth - sourceColWidth - 6
 	if titleColWidth < 20 {
 		titleColWidth = 20
 	}
 
-	m.table.SetColumns([]table.Column{
+	columns := []table.Column{
 		{Title: " \u2610 ", Width: checkboxColWidth},
 		{Title: "Title", Width: titleColWidth},
-		{Title: "Status", Width: statusColWidth},
-		{Title: "Created", Width: createdAtColWidth},
-	})
+	}
+	if sourceColWidth > 0 {
+		columns = append(columns, table.Column{Title: "Source", Width: sourceColWidth})
+	}
+	columns = append(columns,
+		table.Column{Title: "Status", Width: statusColWidth},
+		table.Column{Title: "Created", Width: createdAtColWidth},
+	)
+	m.table.SetColumns(columns)
 
 	var rows []table.Row
-	var filteredTodos []model.Todo
-
-	if m.showAll {
-		filteredTodos = m.todoList.Todos
-	} else if m.showArchivedOnly {
-		filteredTodos = m.todoList.GetArchivedTodos()
-	} else {
-		filteredTodos = m.todoList.GetActiveTodos()
-	}
+	filteredTodos := m.visibleTodos()
 
 	sel := m.table.Cursor()
 	for i, todo := range filteredTodos {
@@ -140,6 +318,10 @@ func (m *TodoTableModel) updateRows() {
 
 			if todo.Completed {
 				status = "Completed"
+			} else if todo.Running() {
+				status = "Tracking"
+			} else if todo.Snoozed(now) {
+				status = "Snoozed"
 			} else {
 				status = "Pending"
 			}
@@ -157,12 +339,20 @@ func (m *TodoTableModel) updateRows() {
 
 			if todo.Completed {
 				status = statusCompleteStyle.Render("Completed")
+			} else if todo.Running() {
+				status = statusTrackingStyle.Render("Tracking")
+			} else if todo.Snoozed(now) {
+				status = statusSnoozedStyle.Render("Snoozed")
 			} else {
 				status = statusPendingStyle.Render("Pending")
 			}
 		}
-		createdAt := model.FormatTimeAgo(todo.CreatedAt)
-		rows = append(rows, table.Row{checkbox, title, status, createdAt})
+		createdAt := m.timeStyle.Format(todo.CreatedAt, now)
+		row := table.Row{checkbox, title}
+		if sourceColWidth > 0 {
+			row = append(row, m.sourceName(todo))
+		}
+		rows = append(rows, append(row, status, createdAt))
 	}
 	m.table.SetRows(rows)
 
@@ -171,18 +361,7 @@ func (m *TodoTableModel) updateRows() {
 	extra := 4
 	helpLines := 0
 	if m.mode == ModeNormal {
-		if m.showHelp {
-			helpLines = 2 + 1
-			if m.bulkActionActive {
-
-				helpLines += 10
-			} else {
-
-				helpLines += 9
-			}
-		} else {
-			helpLines = 2
-		}
+		helpLines = lipgloss.Height(m.helpText())
 	}
 
 	rowsHeight := m.height - extra - helpLines
@@ -210,7 +389,18 @@ func (m TodoTableModel) findTodoByID(id int) *model.Todo {
 }
 
 func (m TodoTableModel) Init() tea.Cmd {
-	return textinput.Blink
+	return tea.Batch(textinput.Blink, m.watchTodoFileCmd(), timerTickCmd())
+}
+
+// toggleTimeStyle switches between relative and absolute times.
+func (m *TodoTableModel) toggleTimeStyle() {
+	m.timeStyle.Absolute = !m.timeStyle.Absolute
+	if m.timeStyle.Absolute {
+		m.SetStatusMessage("Showing absolute times")
+	} else {
+		m.SetStatusMessage("Showing relative times")
+	}
+	m.updateRows()
 }
 
 func (m *TodoTableModel) SetStatusMessage(message string) {
@@ -0,0 +1,32 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type timerTickMsg time.Time
+
+// timerTickCmd redraws the table every second so the elapsed time of a
+// running timer stays current.
+func timerTickCmd() tea.Cmd {
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg { return timerTickMsg(t) })
+}
+
+// timerStatus describes the running timer for the status bar, or returns ""
+// when none is running.
+func (m TodoTableModel) timerStatus() string {
+	todo, ok := m.todoList.RunningTimer()
+	if !ok {
+		return ""
+	}
+	title := []rune(todo.Title)
+	if len(title) > 24 {
+		title = append(title[:23], '…')
+	}
+	elapsed := todo.TimeLog[len(todo.TimeLog)-1].Duration(time.Now()).Round(time.Second)
+	h, mins, s := int(elapsed.Hours()), int(elapsed.Minutes())%60, int(elapsed.Seconds())%60
+	return fmt.Sprintf("⏱ %s %d:%02d:%02d", string(title), h, mins, s)
+}
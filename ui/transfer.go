@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/prime-run/togo/model"
+	"github.com/prime-run/togo/pkg/togo"
+)
+
+// moveToOtherSource saves the list, moves the selected or bulk-selected tasks
+// between the project and global sources and reloads the table.
+func (m *TodoTableModel) moveToOtherSource() tea.Cmd {
+	client, ok := m.store.(*togo.Client)
+	if !ok {
+		m.SetStatusMessage("Switch to a single source to move tasks")
+		return nil
+	}
+	var uids []string
+	if len(m.selectedTodoIDs) > 0 && m.bulkActionActive {
+		for id := range m.selectedTodoIDs {
+			if todo := m.findTodoByID(id); todo != nil {
+				uids = append(uids, todo.UID)
+			}
+		}
+	} else if visible := m.visibleTodos(); m.table.Cursor() < len(visible) {
+		uids = append(uids, visible[m.table.Cursor()].UID)
+	}
+	if len(uids) == 0 {
+		return nil
+	}
+
+	next := togo.Global
+	if m.sourceLabel == togo.Global {
+		next = togo.Project
+	}
+	dst, err := m.openSource(next)
+	if err != nil {
+		m.SetStatusMessage("move failed: " + err.Error())
+		return nil
+	}
+	ctx := context.Background()
+	warning, ok := m.saveBeforeSwitch(ctx)
+	if !ok {
+		return nil
+	}
+	// Saving may renumber tasks added in the table, so look them up again.
+	var ids []int
+	for _, uid := range uids {
+		for _, todo := range m.todoList.Todos {
+			if todo.UID == uid {
+				ids = append(ids, todo.ID)
+			}
+		}
+	}
+	moved, err := client.MoveTo(ctx, dst, ids...)
+	if err != nil {
+		m.SetStatusMessage("move failed: " + err.Error())
+	} else if len(moved) == 1 {
+		m.SetStatusMessage("Task moved to " + next + warning)
+	} else {
+		m.SetStatusMessage(fmt.Sprintf("%d tasks moved to %s%s", len(moved), next, warning))
+	}
+
+	if tasks, err := m.store.Tasks(ctx); err == nil {
+		m.todoList = model.NewTodoListFrom(tasks)
+	}
+	m.selectedTodoIDs = make(map[int]bool)
+	m.bulkActionActive = false
+	m.watchSource()
+	m.updateRows()
+	return tea.Batch(m.forceRelayoutCmd(), m.watchTodoFileCmd())
+}
@@ -1,13 +1,17 @@
 package ui
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/prime-run/togo/hooks"
 	"github.com/prime-run/togo/model"
+	"github.com/prime-run/togo/pkg/togo"
 )
 
 func (m TodoTableModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -18,6 +22,30 @@ func (m TodoTableModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.updateRows()
 		return m, nil
 	}
+	if msg, ok := msg.(todoFileChangedMsg); ok {
+		if msg.generation != m.watchGeneration {
+			return m, nil
+		}
+		m.fileStamps = msg.stamps
+		if n, err := m.reloadFromDisk(); err != nil {
+			m.SetStatusMessage("reload failed: " + err.Error())
+		} else if n == 1 {
+			m.SetStatusMessage("1 change loaded from disk")
+		} else if n > 1 {
+			m.SetStatusMessage(fmt.Sprintf("%d changes loaded from disk", n))
+		}
+		return m, tea.Batch(m.watchTodoFileCmd(), m.forceRelayoutCmd())
+	}
+	if msg, ok := msg.(timerTickMsg); ok {
+		if m.mode == ModePomodoro {
+			return m, tea.Batch(timerTickCmd(), m.advancePomodoro(time.Time(msg), false))
+		}
+		return m, timerTickCmd()
+	}
+	if msg, ok := msg.(pomodoroCommandMsg); ok {
+		m.SetStatusMessage("Pomodoro command failed: " + msg.err.Error())
+		return m, nil
+	}
 	switch m.mode {
 	case ModeViewDetail:
 		switch msg := msg.(type) {
@@ -26,6 +54,33 @@ func (m TodoTableModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case "esc", "q", "enter":
 				m.mode = ModeNormal
 				return m, nil
+			case "T":
+				m.toggleTimeStyle()
+				return m, nil
+			}
+		}
+		return m, nil
+	case ModeStats:
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			switch msg.String() {
+			case "esc", "q", "enter", "S":
+				m.mode = ModeNormal
+				return m, m.forceRelayoutCmd()
+			}
+		}
+		return m, nil
+	case ModePomodoro:
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			switch msg.String() {
+			case " ":
+				m.togglePomodoroPause()
+			case "n":
+				return m, m.advancePomodoro(time.Now(), true)
+			case "esc", "q":
+				m.stopPomodoro()
+				return m, m.forceRelayoutCmd()
 			}
 		}
 		return m, nil
@@ -36,20 +91,27 @@ func (m TodoTableModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case "y", "Y":
 				if m.mode == ModeDeleteConfirm {
 					if len(m.selectedTodoIDs) > 0 && m.bulkActionActive {
-						count := len(m.selectedTodoIDs)
+						count, rejected := 0, false
 						for id := range m.selectedTodoIDs {
-							m.todoList.Delete(id)
+							if m.applyWithHooks(hooks.Delete, id, m.todoList.Delete) {
+								count++
+							} else {
+								rejected = true
+							}
 						}
 						m.selectedTodoIDs = make(map[int]bool)
 						m.bulkActionActive = false
-						m.SetStatusMessage(fmt.Sprintf("%d tasks deleted", count))
+						if !rejected {
+							m.SetStatusMessage(fmt.Sprintf("%d tasks deleted", count))
+						}
 					} else {
 						found := false
-						for i, todo := range m.todoList.Todos {
+						for _, todo := range m.todoList.Todos {
 							if todo.Title == m.actionTitle || strings.Contains(m.actionTitle, todo.Title) {
-								m.todoList.Todos = append(m.todoList.Todos[:i], m.todoList.Todos[i+1:]...)
+								if m.applyWithHooks(hooks.Delete, todo.ID, m.todoList.Delete) {
+									m.SetStatusMessage("Task deleted")
+								}
 								found = true
-								m.SetStatusMessage("Task deleted")
 								break
 							}
 						}
@@ -58,8 +120,9 @@ func (m TodoTableModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 							if err == nil {
 								for _, todo := range m.todoList.Todos {
 									if todo.ID == id {
-										m.todoList.Delete(id)
-										m.SetStatusMessage("Task deleted")
+										if m.applyWithHooks(hooks.Delete, id, m.todoList.Delete) {
+											m.SetStatusMessage("Task deleted")
+										}
 										break
 									}
 								}
@@ -69,14 +132,14 @@ func (m TodoTableModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				} else if m.mode == ModeArchiveConfirm {
 					if len(m.selectedTodoIDs) > 0 && m.bulkActionActive {
 						for id := range m.selectedTodoIDs {
-							m.todoList.Archive(id)
+							m.applyWithHooks(hooks.Archive, id, m.todoList.Archive)
 						}
 						m.selectedTodoIDs = make(map[int]bool)
 						m.bulkActionActive = false
 					} else {
 						for _, todo := range m.todoList.Todos {
 							if todo.Title == m.actionTitle {
-								m.todoList.Archive(todo.ID)
+								m.applyWithHooks(hooks.Archive, todo.ID, m.todoList.Archive)
 								break
 							}
 						}
@@ -98,10 +161,15 @@ func (m TodoTableModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case "enter":
 				title := strings.TrimSpace(m.textInput.Value())
 				if title != "" {
-					m.todoList.Add(title)
+					draft := model.Todo{Title: title, List: m.activeList}
+					if todo, err := m.hooks.Add(context.Background(), m.todoList, draft); err != nil {
+						m.SetStatusMessage("Aborted by hook: " + err.Error())
+					} else {
+						m.queuePostHooks(hooks.PostAdd, todo.ID)
+						m.SetStatusMessage("New task added")
+					}
 					m.textInput.Reset()
 					m.updateRows()
-					m.SetStatusMessage("New task added")
 				}
 				m.mode = ModeNormal
 				return m, m.forceRelayoutCmd()
@@ -135,6 +203,54 @@ func (m TodoTableModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		m.textInput, cmd = m.textInput.Update(msg)
 		return m, cmd
+	case ModeSnooze:
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			switch msg.String() {
+			case "enter":
+				m.snooze()
+				m.textInput.Reset()
+				m.textInput.Placeholder = "Enter new task title"
+				m.mode = ModeNormal
+				m.updateRows()
+				return m, m.forceRelayoutCmd()
+			case "esc":
+				m.textInput.Reset()
+				m.textInput.Placeholder = "Enter new task title"
+				m.mode = ModeNormal
+				return m, nil
+			}
+		}
+		m.textInput, cmd = m.textInput.Update(msg)
+		return m, cmd
+	case ModeEstimate:
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			switch msg.String() {
+			case "enter":
+				estimate, err := model.ParseEstimate(m.textInput.Value())
+				if err != nil {
+					m.SetStatusMessage(err.Error())
+				} else if m.todoList.SetEstimate(m.editTaskID, estimate) {
+					if estimate.IsZero() {
+						m.SetStatusMessage("Estimate cleared")
+					} else {
+						m.SetStatusMessage("Estimated at " + estimate.String())
+					}
+				}
+				m.textInput.Reset()
+				m.textInput.Placeholder = "Enter new task title"
+				m.mode = ModeNormal
+				return m, m.forceRelayoutCmd()
+			case "esc":
+				m.textInput.Reset()
+				m.textInput.Placeholder = "Enter new task title"
+				m.mode = ModeNormal
+				return m, nil
+			}
+		}
+		m.textInput, cmd = m.textInput.Update(msg)
+		return m, cmd
 	case ModeNormal:
 		switch msg := msg.(type) {
 		case tea.KeyMsg:
@@ -146,44 +262,78 @@ func (m TodoTableModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					current = "project"
 				}
 
-				if m.todoFileName != "" {
-					if err := m.todoList.SaveWithSource(m.todoFileName, current); err != nil {
-						m.SetStatusMessage("save failed: " + err.Error())
-					} else {
-						m.SetStatusMessage("Saved to " + current)
+				var warning string
+				if m.store != nil {
+					var ok bool
+					if warning, ok = m.saveBeforeSwitch(context.Background()); !ok {
+						return m, nil
 					}
+					m.SetStatusMessage("Saved to " + current + warning)
 				}
 
 				next := "project"
 				if current == "project" {
 					next = "global"
 				}
-				if m.todoFileName != "" {
-					if newList, err := model.LoadTodoListWithSource(m.todoFileName, next); err == nil {
-						m.todoList = newList
-						m.sourceLabel = next
-
-						if next == "project" {
-							if projectName, hasProject := model.GetProjectRootName(); hasProject {
-								m.projectName = projectName
-							} else {
-								m.projectName = ""
-							}
-						} else {
-							m.projectName = ""
-						}
-
+				if m.store != nil {
+					client, err := m.openSource(next)
+					var tasks []togo.Task
+					if err == nil {
+						tasks, err = client.Tasks(context.Background())
+					}
+					if err == nil {
+						m.todoList = model.NewTodoListFrom(tasks)
 						m.selectedTodoIDs = make(map[int]bool)
 						m.bulkActionActive = false
+						m.activeList = ""
+						m.setStore(client)
 						m.updateRows()
-						m.SetStatusMessage("Source switched to " + next)
-						return m, m.forceRelayoutCmd()
+						m.SetStatusMessage("Source switched to " + next + warning)
+						return m, tea.Batch(m.forceRelayoutCmd(), m.watchTodoFileCmd())
 					} else {
 						m.SetStatusMessage("load failed for " + next)
 					}
 				}
 				return m, nil
-			case ".":
+			case "m":
+				if len(m.table.Rows()) > 0 {
+					return m, m.moveToOtherSource()
+				}
+				return m, nil
+			case "p":
+				if len(m.table.Rows()) > 0 {
+					return m, m.startPomodoro()
+				}
+				return m, nil
+			case "l":
+				m.nextList()
+				if m.activeList == "" {
+					m.SetStatusMessage("Showing all lists")
+				} else {
+					m.SetStatusMessage("Switched to list " + m.activeList)
+				}
+				return m, m.forceRelayoutCmd()
+			case "T":
+				m.toggleTimeStyle()
+				return m, m.forceRelayoutCmd()
+			case "S":
+				m.mode = ModeStats
+				return m, nil
+			case "+":
+				m.togglePlanned()
+				return m, nil
+			case "z":
+				return m, m.startSnooze()
+			case "f":
+				m.focus = !m.focus
+				m.updateRows()
+				if m.focus {
+					m.SetStatusMessage("Showing today's plan")
+				} else {
+					m.SetStatusMessage("Showing all tasks")
+				}
+				return m, m.forceRelayoutCmd()
+			case ".", "?":
 				m.showHelp = !m.showHelp
 				m.updateRows()
 				return m, m.forceRelayoutCmd()
@@ -204,16 +354,24 @@ func (m TodoTableModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			case "t":
 				if len(m.table.Rows()) > 0 {
+					var completed []int
 					if len(m.selectedTodoIDs) > 0 && m.bulkActionActive {
-						count := 0
+						count, rejected := 0, false
 						for id := range m.selectedTodoIDs {
 							todo := m.findTodoByID(id)
 							if todo != nil {
-								m.todoList.Toggle(id)
-								count++
+								wasCompleted := todo.Completed
+								if m.toggleWithHooks(*todo) {
+									if !wasCompleted {
+										completed = append(completed, id)
+									}
+									count++
+								} else {
+									rejected = true
+								}
 							}
 						}
-						if count > 0 {
+						if count > 0 && !rejected {
 							m.SetStatusMessage(fmt.Sprintf("%d tasks updated", count))
 						}
 					} else {
@@ -222,13 +380,18 @@ func (m TodoTableModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 						for _, todo := range m.todoList.Todos {
 							if strings.Contains(selectedTitle, todo.Title) || todo.Title == cleanTitle {
-								m.todoList.Toggle(todo.ID)
-								m.SetStatusMessage("Task updated")
+								if m.toggleWithHooks(todo) {
+									if !todo.Completed {
+										completed = append(completed, todo.ID)
+									}
+									m.SetStatusMessage("Task updated")
+								}
 								break
 							}
 						}
 					}
 					m.updateRows()
+					m.queuePostHooks(hooks.PostComplete, completed...)
 					return m, m.forceRelayoutCmd()
 				}
 			case "n":
@@ -241,8 +404,7 @@ func (m TodoTableModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 								if todo.Archived {
 									m.todoList.Unarchive(id)
 									count++
-								} else {
-									m.todoList.Archive(id)
+								} else if m.applyWithHooks(hooks.Archive, id, m.todoList.Archive) {
 									count++
 								}
 							}
@@ -261,8 +423,7 @@ func (m TodoTableModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 								if todo.Archived {
 									m.todoList.Unarchive(todo.ID)
 									m.SetStatusMessage("Task unarchived")
-								} else {
-									m.todoList.Archive(todo.ID)
+								} else if m.applyWithHooks(hooks.Archive, todo.ID, m.todoList.Archive) {
 									m.SetStatusMessage("Task archived")
 								}
 								m.updateRows()
@@ -287,6 +448,20 @@ func (m TodoTableModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 				}
 				return m, nil
+			case "E":
+				if visible := m.visibleTodos(); len(visible) > 0 && m.table.Cursor() < len(visible) {
+					todo := visible[m.table.Cursor()]
+					m.editTaskID = todo.ID
+					m.textInput.Reset()
+					if !todo.Estimate.IsZero() {
+						m.textInput.SetValue(todo.Estimate.String())
+					}
+					m.textInput.Placeholder = "3, 2.5pt, 90m or 2h (empty clears)"
+					m.textInput.Focus()
+					m.mode = ModeEstimate
+					return m, textinput.Blink
+				}
+				return m, nil
 			case "a":
 				m.mode = ModeAddTask
 				m.textInput.Focus()
@@ -313,15 +488,7 @@ func (m TodoTableModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if len(m.table.Rows()) > 0 {
 					selectedIndex := m.table.Cursor()
 					if selectedIndex >= 0 && selectedIndex < len(m.todoList.Todos) {
-						var filteredTodos []model.Todo
-
-						if m.showAll {
-							filteredTodos = m.todoList.Todos
-						} else if m.showArchivedOnly {
-							filteredTodos = m.todoList.GetArchivedTodos()
-						} else {
-							filteredTodos = m.todoList.GetActiveTodos()
-						}
+						filteredTodos := m.visibleTodos()
 
 						if selectedIndex < len(filteredTodos) {
 							todo := filteredTodos[selectedIndex]
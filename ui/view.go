@@ -2,9 +2,13 @@ package ui
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/prime-run/togo/model"
+	"github.com/prime-run/togo/report"
 )
 
 func (m TodoTableModel) View() string {
@@ -21,17 +25,105 @@ func (m TodoTableModel) View() string {
 			status = statusPendingStyle.Render("Pending")
 		}
 		archivedStatus := ""
-		if todo.Archived {
+		if todo.Archived && !todo.ArchivedAt.IsZero() {
+			archivedStatus = "\nArchived: " + archivedStyle.Render(m.timeStyle.Format(todo.ArchivedAt, time.Now()))
+		} else if todo.Archived {
 			archivedStatus = "\nArchived: " + archivedStyle.Render("Yes")
 		}
-		createdAt := model.FormatTimeAgo(todo.CreatedAt)
+		location := ""
+		if m.isAggregate() {
+			location = "Source: " + createdAtStyle.Render(m.sourceName(*todo)) + "\n"
+		}
+		if todo.List != "" {
+			location += "List: " + createdAtStyle.Render(todo.List) + "\n"
+		}
+		if todo.Location != "" {
+			location += "Location: " + createdAtStyle.Render(todo.Location) + "\n"
+		}
+		if len(todo.Branches) > 0 {
+			location += "Branches: " + createdAtStyle.Render(strings.Join(todo.Branches, ", ")) + "\n"
+		}
+		if len(todo.Commits) > 0 {
+			location += "Commits: " + createdAtStyle.Render(strings.Join(todo.Commits, ", ")) + "\n"
+		}
+		if len(todo.TimeLog) > 0 {
+			tracked := model.FormatDuration(todo.TimeSpent(time.Now()))
+			if todo.Running() {
+				tracked += " (running)"
+			}
+			location += "Time tracked: " + createdAtStyle.Render(tracked) + "\n"
+		}
+		if !todo.Due.IsZero() {
+			location += "Due: " + createdAtStyle.Render(m.timeStyle.Format(todo.Due, time.Now())) + "\n"
+		}
+		if todo.Priority != model.PriorityNone {
+			location += "Priority: " + createdAtStyle.Render(todo.Priority.String()) + "\n"
+		}
+		if blockers := model.Blockers(*todo, m.todoList.Todos); len(blockers) > 0 {
+			titles := make([]string, len(blockers))
+			for i, b := range blockers {
+				titles[i] = b.Title
+			}
+			location += "Blocked by: " + createdAtStyle.Render(strings.Join(titles, ", ")) + "\n"
+		}
+		if todo.Snoozed(time.Now()) {
+			location += "Snoozed until: " + createdAtStyle.Render(m.timeStyle.Format(todo.HiddenUntil, time.Now())) + "\n"
+		}
+		if !todo.PlannedFor.IsZero() {
+			planned := m.timeStyle.Format(todo.PlannedFor, time.Now())
+			deferrals := todo.DeferralsAt(time.Now())
+			if todo.PlannedToday(time.Now()) {
+				planned = "today"
+			}
+			if deferrals == 1 {
+				planned += " (deferred 1 day)"
+			} else if deferrals > 1 {
+				planned += fmt.Sprintf(" (deferred %d days)", deferrals)
+			}
+			location += "Planned: " + createdAtStyle.Render(planned) + "\n"
+		}
+		if !todo.Estimate.IsZero() {
+			location += "Estimate: " + createdAtStyle.Render(todo.Estimate.String()) + "\n"
+		}
+		if n := len(todo.Pomodoros); n > 0 {
+			location += "Pomodoros: " + createdAtStyle.Render(strconv.Itoa(n)) + "\n"
+		}
+		now := time.Now()
+		times := "Created: " + createdAtStyle.Render(m.timeStyle.Format(todo.CreatedAt, now)) + "\n"
+		if !todo.UpdatedAt.IsZero() {
+			times += "Updated: " + createdAtStyle.Render(m.timeStyle.Format(todo.UpdatedAt, now)) + "\n"
+		}
+		if todo.Completed && !todo.CompletedAt.IsZero() {
+			times += "Completed: " + createdAtStyle.Render(m.timeStyle.Format(todo.CompletedAt, now)) + "\n"
+		}
 		taskView := fullTaskViewStyle.Render(
 			taskTitleStyle.Render(todo.Title) + "\n\n" +
 				"Status: " + status + archivedStatus + "\n" +
-				"Created: " + createdAtStyle.Render(createdAt) + "\n\n" +
-				helpStyle.Render("Press Enter to go back"))
+				location +
+				times + "\n" +
+				helpStyle.Render("Press Enter to go back  |  T: relative/absolute times"))
 		return fullScreenStyle.Width(m.width).Height(m.height).Render(taskView)
 	}
+	if m.mode == ModePomodoro {
+		return m.pomodoroView()
+	}
+	if m.mode == ModeStats {
+		todos, title := m.todoList.Todos, "Statistics"
+		if m.activeList != "" {
+			todos, title = nil, title+"  |  list: "+m.activeList
+			for _, todo := range m.todoList.Todos {
+				if todo.InList(m.activeList) {
+					todos = append(todos, todo)
+				}
+			}
+		}
+		now := time.Now()
+		stats := report.BuildStats(todos, now, StatsWeeks)
+		return baseStyle.Width(m.width - 4).Render(
+			titleBarStyle.Render(title) + "\n\n" +
+				RenderStats(stats, now, m.width-8) + "\n\n" +
+				helpStyle.Render("Press Enter to go back"))
+	}
 	if m.mode == ModeDeleteConfirm || m.mode == ModeArchiveConfirm {
 		var confirmMessage string
 		action := "delete"
@@ -55,6 +147,30 @@ func (m TodoTableModel) View() string {
 				helpStyle.Render("Press Enter to save, Esc to cancel"))
 		return fullScreenStyle.Width(m.width).Height(m.height).Render(inputView)
 	}
+	if m.mode == ModeEstimate {
+		title := ""
+		if todo := m.findTodoByID(m.editTaskID); todo != nil {
+			title = todo.Title
+		}
+		inputView := inputStyle.Render(
+			inputPromptStyle.Render("Estimate") + "\n" +
+				createdAtStyle.Render(title) + "\n\n" +
+				m.textInput.View() + "\n\n" +
+				helpStyle.Render("Press Enter to save, Esc to cancel"))
+		return fullScreenStyle.Width(m.width).Height(m.height).Render(inputView)
+	}
+	if m.mode == ModeSnooze {
+		subject := fmt.Sprintf("%d selected tasks", len(m.selectedTodoIDs))
+		if todo := m.findTodoByID(m.editTaskID); todo != nil {
+			subject = todo.Title
+		}
+		inputView := inputStyle.Render(
+			inputPromptStyle.Render("Snooze until") + "\n" +
+				createdAtStyle.Render(subject) + "\n\n" +
+				m.textInput.View() + "\n\n" +
+				helpStyle.Render("Press Enter to save, Esc to cancel"))
+		return fullScreenStyle.Width(m.width).Height(m.height).Render(inputView)
+	}
 	if m.mode == ModeEditTask {
 		inputView := inputStyle.Render(
 			inputPromptStyle.Render("Edit Task") + "\n\n" +
@@ -66,17 +182,37 @@ func (m TodoTableModel) View() string {
 		return baseStyle.Render("No tasks found. Press 'a' to add a new task!")
 	}
 
+	tableView := tableContainerStyle.Render(m.table.View())
+	if m.mode == ModeNormal {
+		return tableView + helpStyle.Render(m.helpText())
+	}
+	return tableView
+}
+
+// helpText returns the status bar and the key help shown below the table in
+// normal mode: one line of the main keys, or all of them once expanded with
+// "." or "?". updateRows sizes the table by its height.
+func (m TodoTableModel) helpText() string {
 	var helpText string
 	var listTitle string
 
 	if m.showArchivedOnly {
 		listTitle = "Archived Tasks"
+	} else if m.showSnoozedOnly {
+		listTitle = "Snoozed Tasks"
 	} else if m.showAll {
 		listTitle = "All Tasks"
 	} else {
 		listTitle = "Active Tasks"
 	}
 
+	if m.focus {
+		listTitle = "Today's Plan"
+	}
+	if m.activeList != "" {
+		listTitle += "  |  list: " + m.activeList
+	}
+
 	sourceText := ""
 	if m.sourceLabel != "" {
 		sourceText = "  |  source: " + m.sourceLabel
@@ -84,6 +220,9 @@ func (m TodoTableModel) View() string {
 			sourceText += " (" + m.projectName + ")"
 		}
 	}
+	if timer := m.timerStatus(); timer != "" {
+		sourceText += "  |  " + timer
+	}
 	leftSide := titleBarStyle.Render(listTitle + sourceText)
 	rightSide := successMessageStyle.Render(m.statusMessage)
 
@@ -97,40 +236,56 @@ func (m TodoTableModel) View() string {
 		),
 	)
 
-	if m.bulkActionActive {
+	key := func(k, desc string) string {
+		return titleBarStyle.Render(k) + " " + desc
+	}
+	switch {
+	case !m.showHelp && m.bulkActionActive:
+		helpText = "\n" + statusBar + "\n" + "Bulk: " + strings.Join([]string{
+			key("t", "toggle"), key("n", "archive"), key("d", "delete"), key("z", "snooze"),
+			key("space", "select"), key("?", "more"),
+		}, "  ")
+	case !m.showHelp:
+		helpText = "\n" + statusBar + "\n" + strings.Join([]string{
+			key("a", "add"), key("t", "toggle"), key("d", "delete"),
+			key("enter", "details"), key("space", "select"), key("q", "quit"), key("?", "more"),
+		}, "  ")
+	case m.bulkActionActive:
 		helpText = "\n" + statusBar + "\n" +
 			"Bulk Mode:" +
 			"\n→ " + confirmBtnStyle.Render("t") + ": toggle completion for all selected" +
 			"\n→ " + confirmBtnStyle.Render("n") + ": toggle archive/unarchive for selected" +
 			"\n→ " + confirmBtnStyle.Render("d") + ": delete selected" +
+			"\n→ " + confirmBtnStyle.Render("z") + ": snooze selected" +
 			"\n→ " + confirmBtnStyle.Render("space") + ": toggle selection" +
 			"\n→ " + confirmBtnStyle.Render("enter") + ": view details" +
 			"\n→ " + confirmBtnStyle.Render("a") + ": add new task" +
+			"\n→ " + confirmBtnStyle.Render("m") + ": move to the other source" +
+			"\n→ " + confirmBtnStyle.Render("l") + ": switch list" +
 			"\n→ " + confirmBtnStyle.Render("s") + ": switch source (project/global)" +
 			"\n→ " + confirmBtnStyle.Render("q") + ": quit" +
-			"\n→ " + confirmBtnStyle.Render(".") + ": toggle help"
-	} else {
+			"\n→ " + confirmBtnStyle.Render("?") + ": fewer keys"
+	default:
 		helpText = "\n" + statusBar + "\n" +
 			"→ " + confirmBtnStyle.Render("t") + ": toggle completion" +
 			"\n→ " + confirmBtnStyle.Render("n") + ": toggle archive/unarchive" +
 			"\n→ " + confirmBtnStyle.Render("d") + ": delete" +
 			"\n→ " + confirmBtnStyle.Render("e") + ": edit task" +
+			"\n→ " + confirmBtnStyle.Render("E") + ": set estimate" +
+			"\n→ " + confirmBtnStyle.Render("p") + ": start a pomodoro" +
+			"\n→ " + confirmBtnStyle.Render("T") + ": relative/absolute times" +
+			"\n→ " + confirmBtnStyle.Render("S") + ": statistics" +
+			"\n→ " + confirmBtnStyle.Render("+") + ": plan/unplan for today" +
+			"\n→ " + confirmBtnStyle.Render("z") + ": snooze" +
+			"\n→ " + confirmBtnStyle.Render("f") + ": focus on today's plan" +
 			"\n→ " + confirmBtnStyle.Render("space") + ": select" +
 			"\n→ " + confirmBtnStyle.Render("enter") + ": view details" +
 			"\n→ " + confirmBtnStyle.Render("a") + ": add new task" +
+			"\n→ " + confirmBtnStyle.Render("m") + ": move to the other source" +
+			"\n→ " + confirmBtnStyle.Render("l") + ": switch list" +
 			"\n→ " + confirmBtnStyle.Render("s") + ": switch source (project/global)" +
 			"\n→ " + confirmBtnStyle.Render("q") + ": quit" +
-			"\n→ " + confirmBtnStyle.Render(".") + ": toggle help"
+			"\n→ " + confirmBtnStyle.Render("?") + ": fewer keys"
 	}
-
-	tableView := tableContainerStyle.Render(m.table.View())
-	if m.mode == ModeNormal {
-		if m.showHelp {
-			help := helpStyle.Render(helpText)
-			return tableView + help
-		}
-		hint := helpStyle.Render("\n→ " + confirmBtnStyle.Render(".") + ": toggle help")
-		return tableView + hint
-	}
-	return tableView
+	return helpText
 }